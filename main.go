@@ -1,17 +1,25 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
+	"github.com/maxwelbm/rabbix/pkg/add"
 	"github.com/maxwelbm/rabbix/pkg/batch"
 	"github.com/maxwelbm/rabbix/pkg/cache"
 	"github.com/maxwelbm/rabbix/pkg/conf"
+	"github.com/maxwelbm/rabbix/pkg/edit"
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/get"
 	"github.com/maxwelbm/rabbix/pkg/health"
 	"github.com/maxwelbm/rabbix/pkg/list"
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/publish"
+	"github.com/maxwelbm/rabbix/pkg/record"
 	"github.com/maxwelbm/rabbix/pkg/request"
 	"github.com/maxwelbm/rabbix/pkg/run"
+	"github.com/maxwelbm/rabbix/pkg/scenario"
 	"github.com/maxwelbm/rabbix/pkg/sett"
+	"github.com/maxwelbm/rabbix/pkg/test"
 	"github.com/spf13/cobra"
 )
 
@@ -19,13 +27,35 @@ var root = &cobra.Command{
 	Use:   "rabbix",
 	Short: "Rabbix é uma CLI para testar filas do RabbitMQ com JSON dinâmico",
 	Long: `Rabbix é uma ferramenta de linha de comando para facilitar testes de filas RabbitMQ.
-Você pode adicionar, listar e executar casos de teste baseados em JSON.`,
+Você pode adicionar, listar e executar casos de teste baseados em JSON.
+
+Códigos de saída:
+  0    sucesso
+  1    erro genérico (ex: argumentos inválidos)
+  2    configuração, flags ou caso de teste inválidos
+  3    caso de teste não encontrado
+  4    falha na publicação
+  5    falha parcial no lote
+  6    falha nas asserções do expect
+  7    tempo total de execução esgotado
+  130  execução interrompida com Ctrl+C`,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := output.Validate(); err != nil {
+			cmd.SilenceUsage = true
+			return exitcode.Wrap(exitcode.Config, err)
+		}
+
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Use um dos subcommands. Ex: rabbix add --help")
+		output.Println("Use um dos subcommands. Ex: rabbix add --help")
 	},
 }
 
 func init() {
+	output.Register(root.PersistentFlags())
+
 	settings := sett.New()
 
 	cached := cache.New(settings)
@@ -33,18 +63,32 @@ func init() {
 	batched := batch.New(settings, cached, requested)
 	r := run.New(settings, cached, requested)
 	c := conf.New(settings)
+	a := add.New(settings, cached)
+	ed := edit.New(settings, cached)
+	g := get.New(requested)
+	rec := record.New(settings, cached, requested)
+	sc := scenario.New(settings, requested)
+	pub := publish.New(requested)
+	tst := test.New(settings, cached)
 
+	root.AddCommand(a.CmdAdd())
 	root.AddCommand(c.CmdConf())
 	root.AddCommand(health.CmdHealth(settings))
 	root.AddCommand(cached.CmdCache())
+	root.AddCommand(ed.CmdEdit())
+	root.AddCommand(g.CmdGet())
 	root.AddCommand(batched.CmdBatch())
 	root.AddCommand(list.CmdList(settings))
+	root.AddCommand(rec.CmdRecord())
+	root.AddCommand(pub.CmdPublish())
 	root.AddCommand(r.CmdRun())
+	root.AddCommand(sc.CmdScenario())
+	root.AddCommand(tst.CmdTest())
 }
 
 func main() {
 	if err := root.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		output.Printf("❌ %v\n", err)
+		os.Exit(exitcode.Code(err))
 	}
 }
@@ -0,0 +1,195 @@
+package add
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/maxwelbm/rabbix/pkg/cache"
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	"github.com/maxwelbm/rabbix/pkg/sett"
+	"github.com/spf13/cobra"
+)
+
+type Add struct {
+	settings sett.SettItf
+	Cache    cache.CacheItf
+}
+
+func New(
+	settings sett.SettItf,
+	cache cache.CacheItf,
+) *Add {
+	return &Add{
+		settings: settings,
+		Cache:    cache,
+	}
+}
+
+func (a *Add) CmdAdd() *cobra.Command {
+	var (
+		routeKey string
+		exchange string
+		vhost    string
+		headers  []string
+		payload  string
+		file     string
+		force    bool
+	)
+
+	var cmd = &cobra.Command{
+		Use:   "add [test-name]",
+		Short: "Cria um novo caso de teste",
+		Long: `Cria um novo caso de teste no diretório de saída da configuração ativa.
+O payload pode ser informado inline, por arquivo ou pela entrada padrão.
+Objetos JSON são salvos em 'json_pool'; arrays, texto e conteúdo binário em 'payload'.
+Exemplos:
+  rabbix add pedido-criado --route-key pedido.criado --payload '{"id": 1}'
+  rabbix add pedido-criado --route-key pedido.criado --file payload.json --header x-tenant=acme
+  cat payload.json | rabbix add pedido-criado --route-key pedido.criado`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			testName := strings.TrimSpace(args[0])
+			if strings.ContainsAny(testName, `/\`) {
+				return exitcode.New(exitcode.Config,
+					"nome de teste inválido '%s': não pode conter separadores de diretório", testName)
+			}
+
+			raw, err := readPayload(cmd, payload, file)
+			if err != nil {
+				return exitcode.New(exitcode.Config, "erro ao ler payload: %w", err)
+			}
+
+			tc := rabbix.TestCase{
+				Name:     testName,
+				RouteKey: routeKey,
+				Exchange: exchange,
+				Vhost:    vhost,
+				JSONPool: map[string]any{},
+			}
+
+			if len(strings.TrimSpace(string(raw))) > 0 {
+				tc.JSONPool, tc.Payload = rabbix.DetectPayload(raw)
+			}
+
+			tc.Headers, err = parseHeaders(headers)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
+			if err := tc.Validate(); err != nil {
+				return exitcode.New(exitcode.Config, "caso de teste inválido: %w", err)
+			}
+
+			// Carrega configuração para obter diretório de saída
+			settings := a.settings.LoadSettings()
+			outputDir := settings["output_dir"]
+			if outputDir == "" {
+				home, _ := os.UserHomeDir()
+				outputDir = filepath.Join(home, ".rabbix", "tests")
+			}
+
+			testPath := filepath.Join(outputDir, testName+".json")
+			if _, err := os.Stat(testPath); err == nil && !force {
+				return exitcode.New(exitcode.Config,
+					"o teste '%s' já existe em %s (use --force para sobrescrever)", testName, testPath)
+			}
+
+			data, err := json.MarshalIndent(tc, "", "  ")
+			if err != nil {
+				return fmt.Errorf("erro ao serializar caso de teste: %w", err)
+			}
+
+			_ = os.MkdirAll(outputDir, os.ModePerm)
+
+			if err := os.WriteFile(testPath, data, 0644); err != nil {
+				return fmt.Errorf("erro ao salvar teste: %w", err)
+			}
+
+			// Atualiza o cache para que o novo teste apareça no autocomplete
+			a.Cache.SyncCacheWithFileSystem()
+
+			output.Printf("✅ Teste '%s' salvo em %s\n", testName, testPath)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&routeKey, "route-key", "r", "",
+		"Routing key usada na publicação da mensagem")
+	cmd.Flags().StringVar(&exchange, "exchange", "",
+		"Exchange de destino (vazio usa o da configuração ativa)")
+	cmd.Flags().StringVar(&vhost, "vhost", "",
+		"Virtual host de destino (vazio usa o da configuração ativa)")
+	cmd.Flags().StringArrayVarP(&headers, "header", "H", nil,
+		"Header da mensagem no formato 'chave=valor' (pode ser repetido)")
+	cmd.Flags().StringVarP(&payload, "payload", "p", "",
+		"Payload inline (objeto JSON, outro valor JSON ou texto)")
+	cmd.Flags().StringVarP(&file, "file", "f", "",
+		"Arquivo com o payload ('-' para ler da entrada padrão)")
+	cmd.Flags().BoolVar(&force, "force", false,
+		"Sobrescreve o teste caso ele já exista")
+
+	return cmd
+}
+
+// readPayload obtém o payload inline, de um arquivo ou da entrada padrão
+func readPayload(cmd *cobra.Command, payload, file string) ([]byte, error) {
+	if payload != "" && file != "" {
+		return nil, fmt.Errorf("use apenas uma das opções --payload ou --file")
+	}
+
+	if payload != "" {
+		return []byte(payload), nil
+	}
+
+	if file == "-" {
+		return io.ReadAll(cmd.InOrStdin())
+	}
+
+	if file != "" {
+		return os.ReadFile(file)
+	}
+
+	// Sem flags, lê da entrada padrão apenas quando ela não é um terminal
+	if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice == 0 {
+		return io.ReadAll(cmd.InOrStdin())
+	}
+
+	return nil, nil
+}
+
+// parseHeaders converte pares 'chave=valor' em um mapa de headers
+func parseHeaders(pairs []string) (map[string]any, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	headers := map[string]any{}
+
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("header inválido '%s' (esperado 'chave=valor')", pair)
+		}
+
+		key := strings.TrimSpace(parts[0])
+
+		// Valores JSON (números, booleanos) são preservados com o tipo original
+		var value any
+		if err := json.Unmarshal([]byte(parts[1]), &value); err != nil {
+			value = parts[1]
+		}
+
+		headers[key] = value
+	}
+
+	return headers, nil
+}
@@ -0,0 +1,109 @@
+package add
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/maxwelbm/rabbix/pkg/cache"
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+)
+
+type fakeSettings map[string]string
+
+func (f fakeSettings) LoadSettings() map[string]string { return f }
+func (f fakeSettings) SaveSettings(map[string]string)  {}
+func (f fakeSettings) GetBaseDir() string              { return "" }
+
+// fakeCache conta as sincronizações feitas após salvar o teste
+type fakeCache struct {
+	cache.CacheItf
+
+	syncs int
+}
+
+func (f *fakeCache) SyncCacheWithFileSystem() {
+	f.syncs++
+}
+
+func runAdd(t *testing.T, dir, stdin string, args ...string) (*fakeCache, error) {
+	t.Helper()
+
+	cache := &fakeCache{}
+	cmd := New(fakeSettings{"output_dir": dir}, cache).CmdAdd()
+	cmd.SetArgs(args)
+	cmd.SetIn(strings.NewReader(stdin))
+
+	return cache, cmd.Execute()
+}
+
+func TestAddSavesTestCase(t *testing.T) {
+	dir := t.TempDir()
+
+	cache, err := runAdd(t, dir, `{"order":{"id":1}}`,
+		"pedido-criado", "--route-key", "pedido.criado", "--file", "-", "-H", "x-retry=3", "-H", "x-tenant=acme")
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "pedido-criado.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tc rabbix.TestCase
+	if err := json.Unmarshal(data, &tc); err != nil {
+		t.Fatal(err)
+	}
+
+	want := rabbix.TestCase{
+		Name:     "pedido-criado",
+		RouteKey: "pedido.criado",
+		JSONPool: map[string]any{"order": map[string]any{"id": float64(1)}},
+		Headers:  map[string]any{"x-retry": float64(3), "x-tenant": "acme"},
+	}
+
+	if !reflect.DeepEqual(tc, want) {
+		t.Errorf("caso salvo = %+v, esperado %+v", tc, want)
+	}
+
+	if cache.syncs != 1 {
+		t.Errorf("cache sincronizado %d vez(es), esperado 1", cache.syncs)
+	}
+}
+
+func TestAddErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := runAdd(t, dir, "", "pedido", "--route-key", "pedidos", "--payload", "{}"); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "teste existente", args: []string{"pedido", "-r", "pedidos"}, wantErr: "--force"},
+		{name: "nome com diretório", args: []string{"../pedido", "-r", "pedidos"}, wantErr: "separadores"},
+		{name: "sem route key", args: []string{"outro"}, wantErr: "'route_key' é obrigatório"},
+		{name: "header inválido", args: []string{"outro", "-r", "pedidos", "-H", "x-tenant"}, wantErr: "header inválido"},
+		{name: "duas fontes", args: []string{"outro", "-r", "pedidos", "-p", "{}", "-f", "a.json"}, wantErr: "apenas uma"},
+	}
+
+	for _, tt := range tests {
+		_, err := runAdd(t, dir, "", tt.args...)
+		if exitcode.Code(err) != exitcode.Config || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: erro = %v (código %d), esperado configuração contendo %q",
+				tt.name, err, exitcode.Code(err), tt.wantErr)
+		}
+	}
+
+	if _, err := runAdd(t, dir, "", "pedido", "-r", "pedidos.v2", "--force"); err != nil {
+		t.Errorf("add --force: %v", err)
+	}
+}
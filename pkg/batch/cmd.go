@@ -1,24 +1,53 @@
 package batch
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/maxwelbm/rabbix/pkg/cache"
+	"github.com/maxwelbm/rabbix/pkg/dataset"
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/expect"
+	"github.com/maxwelbm/rabbix/pkg/interrupt"
+	"github.com/maxwelbm/rabbix/pkg/output"
 	"github.com/maxwelbm/rabbix/pkg/rabbix"
 	"github.com/maxwelbm/rabbix/pkg/request"
 	"github.com/maxwelbm/rabbix/pkg/sett"
+	"github.com/maxwelbm/rabbix/pkg/tmpl"
 	"github.com/spf13/cobra"
 )
 
 var (
 	batchConcurrency int
 	batchDelay       int
+	batchExchange    string
+	batchVhost       string
+	batchTransport   string
+
+	batchAllowUnroutable bool
+	batchReports         []string
+	batchVars            []string
+	batchData            string
+
+	batchRetry          request.RetryPolicy
+	batchOverallTimeout time.Duration
+
+	batchRate     float64
+	batchDuration time.Duration
+	batchRampUp   time.Duration
+	batchTotal    int
 )
 
 type Batch struct {
@@ -47,7 +76,26 @@ func (b *Batch) CmdBatch() *cobra.Command {
 Exemplos:
   rabbix batch teste1 teste2 teste3
   rabbix batch --concurrency 5 --delay 1000 teste1 teste2
-  rabbix batch --all  # executa todos os testes disponíveis`,
+  rabbix batch --all  # executa todos os testes disponíveis
+  rabbix batch --all --report junit=rabbix.xml --report json=rabbix.json
+  rabbix batch pedido-criado --rate 200 --duration 5m --ramp-up 30s -c 20
+  rabbix batch teste1 teste2 --total 1000 -c 10  # carga sem limite de taxa
+  rabbix batch --all --timeout 5s --overall-timeout 2m
+  rabbix batch criar-pedido pagar-pedido -c 1 --var tenant=acme
+  rabbix batch pedido-criado --data pedidos.csv -c 5
+Os testes iniciam na ordem informada. Valores guardados pelo bloco 'capture' de um
+teste ficam disponíveis como {{vars.nome}} nos testes seguintes; use -c 1 para que
+cada teste só comece após o anterior terminar. Publicações cujo expect observa a
+mesma fila também exigem -c 1, já que as mensagens lidas são consumidas.
+Com --data cada linha de um arquivo CSV ou JSON Lines publica os testes informados,
+com as colunas aplicadas: caminhos do json_pool (ex: order.id, order.qty:int),
+headers (headers.tenant) ou a route key (route_key). O resumo e os relatórios
+identificam a linha de cada publicação.
+O comando termina com código diferente de zero quando algum teste falha.
+Ctrl+C interrompe o agendamento de novas publicações, aguarda as que estão em
+andamento e ainda exibe o resumo e grava os relatórios com os resultados parciais.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			b.Cache.SyncCacheWithFileSystem()
 
@@ -70,7 +118,7 @@ Exemplos:
 
 			return suggestions, cobra.ShellCompDirectiveNoFileComp
 		},
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			settings := b.settings.LoadSettings()
 			outputDir := settings["output_dir"]
 			if outputDir == "" {
@@ -78,14 +126,47 @@ Exemplos:
 				outputDir = filepath.Join(home, ".rabbix", "tests")
 			}
 
+			reports, err := ParseReports(batchReports)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
+			if err := batchRetry.Validate(); err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
+			if batchOverallTimeout < 0 {
+				return exitcode.New(exitcode.Config, "--overall-timeout não pode ser negativo")
+			}
+
+			load := LoadOptions{
+				Rate:            batchRate,
+				Duration:        batchDuration,
+				RampUp:          batchRampUp,
+				Total:           batchTotal,
+				Concurrency:     batchConcurrency,
+				AllowUnroutable: batchAllowUnroutable,
+				Retry:           batchRetry,
+			}
+
+			if err := load.Validate(); err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
+			// Compartilhado entre os testes para que {{seq}} avance a cada mensagem
+			// e as variáveis capturadas cheguem aos testes seguintes
+			engine := tmpl.New()
+			if err := engine.SetVarPairs(batchVars); err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
 			var testNames []string
 
 			// Se --all foi especificado, carrega todos os testes
 			if all, _ := cmd.Flags().GetBool("all"); all {
 				files, err := os.ReadDir(outputDir)
 				if err != nil {
-					fmt.Printf("❌ Erro ao listar testes: %v\n", err)
-					return
+					return exitcode.New(exitcode.Config, "erro ao listar testes: %w", err)
 				}
 
 				for _, file := range files {
@@ -99,27 +180,63 @@ Exemplos:
 			}
 
 			if len(testNames) == 0 {
-				fmt.Println("❌ Nenhum teste especificado. Use 'rabbix batch --help' para ver as opções.")
-				return
+				return exitcode.New(exitcode.Config,
+					"nenhum teste especificado. Use 'rabbix batch --help' para ver as opções")
+			}
+
+			var rows []dataset.Row
+
+			if batchData != "" {
+				if rows, err = dataset.Load(batchData); err != nil {
+					if errors.Is(err, fs.ErrNotExist) {
+						return exitcode.New(exitcode.NotFound, "arquivo de dados '%s' não encontrado", batchData)
+					}
+
+					return exitcode.New(exitcode.Config, "erro ao ler '%s': %w", batchData, err)
+				}
+
+				if len(rows) == 0 {
+					return exitcode.New(exitcode.Config, "o arquivo de dados '%s' não possui linhas", batchData)
+				}
+			}
+
+			output.Printf("🚀 Executando %d teste(s) em lote\n", len(testNames))
+
+			if load.Enabled() {
+				printLoadHeader(load)
+
+				if cmd.Flags().Changed("delay") {
+					output.Println("⚠️  --delay é ignorado no modo de carga; use --rate para controlar a taxa")
+				}
+			} else {
+				output.Printf("⚙️  Concorrência: %d | Delay: %dms\n", batchConcurrency, batchDelay)
 			}
 
-			fmt.Printf("🚀 Executando %d teste(s) em lote\n", len(testNames))
-			fmt.Printf("⚙️  Concorrência: %d | Delay: %dms\n", batchConcurrency, batchDelay)
-			fmt.Println("─────────────────────────────────────")
+			output.Println("─────────────────────────────────────")
 
 			// Carrega todos os casos de teste
-			var testCases []rabbix.TestCase
+			var (
+				testCases []rabbix.TestCase
+				skipped   int
+				missing   int
+				captures  bool
+			)
+
 			for _, testName := range testNames {
 				testPath := filepath.Join(outputDir, testName+".json")
 				data, err := os.ReadFile(testPath)
 				if err != nil {
-					fmt.Printf("⚠️  Pulando teste '%s': arquivo não encontrado\n", testName)
+					output.Printf("⚠️  Pulando teste '%s': arquivo não encontrado\n", testName)
+					missing++
+
 					continue
 				}
 
 				var tc rabbix.TestCase
 				if err := json.Unmarshal(data, &tc); err != nil {
-					fmt.Printf("⚠️  Pulando teste '%s': erro no JSON: %v\n", testName, err)
+					output.Printf("⚠️  Pulando teste '%s': erro no JSON: %v\n", testName, err)
+					skipped++
+
 					continue
 				}
 
@@ -127,43 +244,205 @@ Exemplos:
 					tc.Name = testName
 				}
 
+				if batchExchange != "" {
+					tc.Exchange = batchExchange
+				}
+
+				if batchVhost != "" {
+					tc.Vhost = batchVhost
+				}
+
+				if batchTransport != "" {
+					tc.Transport = batchTransport
+				}
+
+				// Com várias mensagens por teste, as filas observadas se misturariam
+				if tc.Expect != nil && load.Enabled() {
+					output.Printf("⚠️  O bloco expect do teste '%s' é ignorado no modo de carga\n", testName)
+					tc.Expect = nil
+				}
+
+				if tc.Expect != nil {
+					if err := tc.Expect.Validate(); err != nil {
+						output.Printf("⚠️  Pulando teste '%s': expect inválido: %v\n", testName, err)
+						skipped++
+
+						continue
+					}
+				}
+
+				if err := rabbix.ValidateCaptures(tc.Capture); err != nil {
+					output.Printf("⚠️  Pulando teste '%s': capture inválido: %v\n", testName, err)
+					skipped++
+
+					continue
+				}
+
+				// O batch não usa o modo request/reply, então não há resposta para capturar
+				if rabbix.UsesReply(tc.Capture) {
+					output.Printf("⚠️  Pulando teste '%s': capture com from: reply exige 'rabbix run --rpc'\n", testName)
+					skipped++
+
+					continue
+				}
+
+				if len(tc.Capture) > 0 {
+					captures = true
+				}
+
 				testCases = append(testCases, tc)
 			}
 
-			if len(testCases) == 0 {
-				fmt.Println("❌ Nenhum teste válido encontrado.")
-				return
+			if captures && !load.Enabled() && batchConcurrency > 1 {
+				output.Println("⚠️  Com --concurrency maior que 1 um teste pode começar antes de o anterior " +
+					"capturar suas variáveis; use -c 1 para encadear os testes")
 			}
 
+			publications, invalid := plan(testCases, rows)
+			skipped += invalid
+
+			// Em paralelo, cada expect consumiria as mensagens publicadas pelo outro
+			if queue, shared := sharedExpectQueue(publications); shared && batchConcurrency > 1 {
+				return exitcode.New(exitcode.Config, "mais de uma publicação observa a fila '%s' no expect; "+
+					"use -c 1 para que uma não consuma as mensagens da outra", queue)
+			}
+
+			if len(publications) == 0 {
+				if skipped == 0 {
+					return exitcode.New(exitcode.NotFound, "nenhum teste válido encontrado")
+				}
+
+				return exitcode.New(exitcode.Config, "nenhum teste válido encontrado")
+			}
+
+			if len(rows) > 0 {
+				output.Printf("📄 Dados: %s (%d linha(s), %d publicação(ões))\n", batchData, len(rows), len(publications))
+			}
+
+			// Mantém a conexão do transporte aberta durante todo o lote
+			defer func() {
+				if err := b.request.Close(); err != nil {
+					output.Printf("❌ Erro ao encerrar conexão: %v\n", err)
+				}
+			}()
+
+			// Ctrl+C interrompe o agendamento; o tempo total cancela também as publicações em andamento
+			scope := interrupt.New(cmd.Context(), batchOverallTimeout)
+			defer scope.Close()
+
 			// Executa os testes com controle de concorrência
-			results := b.executeBatch(testCases, batchConcurrency, time.Duration(batchDelay)*time.Millisecond)
+			startTime := time.Now()
+
+			var (
+				results []BatchResult
+				notRun  []string
+			)
+
+			if load.Enabled() {
+				results = b.executeLoad(scope, publications, engine, load)
+			} else {
+				results, notRun = b.executeBatch(scope, publications, engine, batchConcurrency,
+					time.Duration(batchDelay)*time.Millisecond, batchAllowUnroutable, batchRetry)
+			}
+
+			execution := Execution{Results: results, Elapsed: time.Since(startTime), NotRun: notRun}
+
+			interrupted := scope.Err()
+			if interrupted != nil {
+				execution.Interrupted = interrupted.Error()
+			}
+
+			elapsed := execution.Elapsed
 
 			// Exibe resumo final
-			fmt.Println("─────────────────────────────────────")
-			fmt.Printf("📊 Resumo da execução:\n")
+			output.Println("─────────────────────────────────────")
+			output.Printf("📊 Resumo da execução:\n")
 
 			success := 0
 			failed := 0
+			assertionFailed := 0
+			routed := 0
+			unrouted := 0
 			for _, result := range results {
-				if result.Success {
+				switch {
+				case result.Success:
 					success++
-				} else {
+				case result.assertionFailed():
+					assertionFailed++
+					failed++
+				default:
 					failed++
 				}
+
+				// Só conta o roteamento das publicações aceitas pelo broker
+				if result.Status >= 200 && result.Status < 300 {
+					if result.Routed {
+						routed++
+					} else {
+						unrouted++
+					}
+				}
 			}
 
-			fmt.Printf("✅ Sucessos: %d\n", success)
-			fmt.Printf("❌ Falhas: %d\n", failed)
-			fmt.Printf("⏱️  Tempo total: %v\n", calculateTotalTime(results))
+			output.Printf("✅ Sucessos: %d\n", success)
+			output.Printf("❌ Falhas: %d\n", failed)
+			output.Printf("📬 Roteadas: %d | Não roteadas: %d\n", routed, unrouted)
+
+			if interrupted != nil {
+				output.Printf("⚠️  Resumo parcial (%v)\n", interrupted)
+			}
+
+			if len(notRun) > 0 {
+				output.Printf("⏭️  Não executados: %d\n", len(notRun))
+			}
+
+			overall := computeStats("", results, elapsed)
+			tests := statsByTest(results)
+			printStats(overall, tests)
 
 			if failed > 0 {
-				fmt.Println("\n🔍 Detalhes das falhas:")
-				for _, result := range results {
-					if !result.Success {
-						fmt.Printf("  • %s: %s\n", result.TestName, result.Error)
+				output.Println("\n🔍 Detalhes das falhas:")
+				for _, group := range groupFailures(results) {
+					details := group.result.Error
+					if group.result.Attempts > 1 {
+						details += fmt.Sprintf(" [%d tentativas]", group.result.Attempts)
+					}
+
+					if group.count > 1 {
+						details += fmt.Sprintf(" (x%d)", group.count)
+					}
+
+					output.Printf("  • %s: %s\n", group.result.label(), details)
+
+					for _, assertion := range group.result.Assertions {
+						if !assertion.Passed {
+							output.Printf("      %s\n", assertion)
+						}
 					}
 				}
 			}
+
+			if failedRows := failedRows(results); len(failedRows) > 0 {
+				output.Printf("📄 Linhas com falha em %s: %s\n", batchData, strings.Join(failedRows, ", "))
+			}
+
+			for _, report := range reports {
+				if err := report.Write(execution); err != nil {
+					return err
+				}
+
+				output.Printf("📄 Relatório %s salvo em %s\n", report.Format, report.Path)
+			}
+
+			if err := output.Render(newSummary(execution), statsTable(overall, tests), nil); err != nil {
+				return err
+			}
+
+			if interrupted != nil {
+				return interrupted
+			}
+
+			return exitError(success, failed, assertionFailed, skipped+missing)
 		},
 	}
 
@@ -173,21 +452,169 @@ Exemplos:
 		"Delay em milissegundos entre execuções (0 = sem delay)")
 	cmd.Flags().BoolP("all", "a", false,
 		"Executa todos os testes disponíveis")
+	cmd.Flags().StringVar(&batchExchange, "exchange", "",
+		"Exchange de destino para todos os testes (sobrescreve o do caso de teste e o da configuração)")
+	cmd.Flags().StringVar(&batchVhost, "vhost", "",
+		"Virtual host de destino para todos os testes (sobrescreve o do caso de teste e o da configuração)")
+	cmd.Flags().StringVar(&batchTransport, "transport", "",
+		"Transporte de publicação: http (API de gerenciamento) ou amqp")
+	cmd.Flags().BoolVar(&batchAllowUnroutable, "allow-unroutable", false,
+		"Considera sucesso mensagens publicadas que não foram roteadas para nenhuma fila")
+	cmd.Flags().Float64Var(&batchRate, "rate", 0,
+		"Modo de carga: taxa alvo em mensagens por segundo, em rodízio entre os testes")
+	cmd.Flags().DurationVar(&batchDuration, "duration", 0,
+		"Modo de carga: tempo total de publicação (ex: 30s, 5m)")
+	cmd.Flags().DurationVar(&batchRampUp, "ramp-up", 0,
+		"Modo de carga: tempo para a taxa subir de zero até --rate")
+	cmd.Flags().IntVar(&batchTotal, "total", 0,
+		"Modo de carga: quantidade total de mensagens a publicar")
+	batchRetry.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&batchOverallTimeout, "overall-timeout", 0,
+		"Tempo máximo do lote completo; publicações em andamento são canceladas (0 = sem limite)")
+	cmd.Flags().StringVar(&batchData, "data", "",
+		"Arquivo CSV ou JSON Lines (.jsonl); cada linha publica os testes informados")
+	cmd.Flags().StringArrayVar(&batchVars, "var", nil,
+		"Variável no formato 'nome=valor', disponível como {{vars.nome}}; pode ser repetido")
+	cmd.Flags().StringArrayVar(&batchReports, "report", nil,
+		"Gera relatório no formato 'junit=arquivo.xml' ou 'json=arquivo.json' (pode ser repetido)")
 
 	return cmd
 }
 
+// publication é uma publicação do lote: o caso de teste, já com a linha do --data
+// aplicada quando houver
+type publication struct {
+	testCase rabbix.TestCase
+	// row é a linha do --data; zero sem arquivo de dados
+	row int
+}
+
+// sharedExpectQueue retorna a primeira fila observada pelo expect de mais de uma publicação
+func sharedExpectQueue(publications []publication) (string, bool) {
+	seen := map[string]bool{}
+
+	for _, item := range publications {
+		observed := item.testCase.Expect
+		if observed == nil {
+			continue
+		}
+
+		vhost := observed.Vhost
+		if vhost == "" {
+			vhost = item.testCase.Vhost
+		}
+
+		key := vhost + "\x00" + observed.Queue
+		if seen[key] {
+			return observed.Queue, true
+		}
+
+		seen[key] = true
+	}
+
+	return "", false
+}
+
+// plan monta as publicações do lote. Com --data cada linha publica todos os testes,
+// na ordem informada; testes incompatíveis com as colunas são pulados e contados
+func plan(testCases []rabbix.TestCase, rows []dataset.Row) ([]publication, int) {
+	if len(rows) == 0 {
+		publications := make([]publication, 0, len(testCases))
+		for _, tc := range testCases {
+			publications = append(publications, publication{testCase: tc})
+		}
+
+		return publications, 0
+	}
+
+	var (
+		perTest [][]publication
+		skipped int
+	)
+
+	for _, tc := range testCases {
+		applied := make([]publication, 0, len(rows))
+
+		var err error
+
+		for _, row := range rows {
+			var msg rabbix.TestCase
+			if msg, err = row.Apply(tc); err != nil {
+				break
+			}
+
+			applied = append(applied, publication{testCase: msg, row: row.Line})
+		}
+
+		if err != nil {
+			output.Printf("⚠️  Pulando teste '%s': %v\n", tc.Name, err)
+			skipped++
+
+			continue
+		}
+
+		perTest = append(perTest, applied)
+	}
+
+	publications := make([]publication, 0, len(rows)*len(perTest))
+
+	for i := range rows {
+		for _, applied := range perTest {
+			publications = append(publications, applied[i])
+		}
+	}
+
+	return publications, skipped
+}
+
+func (p publication) label() string {
+	return label(p.testCase.Name, p.row)
+}
+
+// label identifica a publicação nas mensagens e relatórios, com a linha do --data
+func label(name string, row int) string {
+	if row == 0 {
+		return name
+	}
+
+	return fmt.Sprintf("%s (linha %d)", name, row)
+}
+
 type BatchResult struct {
 	TestName string
+	// Row é a linha do --data que originou a publicação
+	Row      int
 	Success  bool
 	Error    string
+	Started  time.Time
 	Duration time.Duration
+	// Attempts é o número de tentativas de publicação, incluindo a primeira
+	Attempts int
 	Status   int
+	Routed   bool
 	Response string
+	// Captured traz as variáveis guardadas pelo bloco capture do teste
+	Captured map[string]any
+	// Assertions traz o resultado do bloco expect, quando o teste define um
+	Assertions []expect.Assertion
 }
 
-func (b *Batch) executeBatch(testCases []rabbix.TestCase, concurrency int, delay time.Duration) []BatchResult {
-	var results []BatchResult
+// executeBatch executa cada publicação uma vez, iniciando-as na ordem informada.
+// Após uma interrupção os testes que ainda não começaram são retornados, em ordem,
+// como não executados
+func (b *Batch) executeBatch(
+	scope *interrupt.Scope,
+	publications []publication,
+	engine *tmpl.Engine,
+	concurrency int,
+	delay time.Duration,
+	allowUnroutable bool,
+	retry request.RetryPolicy,
+) ([]BatchResult, []string) {
+	var (
+		results []BatchResult
+		skipped []int
+	)
 
 	var mutex sync.Mutex
 
@@ -198,83 +625,311 @@ func (b *Batch) executeBatch(testCases []rabbix.TestCase, concurrency int, delay
 
 	startTime := time.Now()
 
-	for i, tc := range testCases {
+schedule:
+	for i, item := range publications {
+		// Adquire o semáforo antes de iniciar a goroutine para manter a ordem dos testes
+		select {
+		case semaphore <- struct{}{}:
+		case <-scope.Schedule.Done():
+			for index := i; index < len(publications); index++ {
+				skipped = append(skipped, index)
+			}
+
+			break schedule
+		}
+
 		wg.Add(1)
 
-		go func(index int, testCase rabbix.TestCase) {
+		go func(index int, item publication) {
 			defer wg.Done()
-
-			// Adquire semáforo para controlar concorrência
-			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			// Aplica delay se não for o primeiro teste
-			if index > 0 && delay > 0 {
-				time.Sleep(delay)
-			}
+			// Aplica delay se não for o primeiro teste; a espera termina com a interrupção
+			if (index > 0 && !scope.Sleep(delay)) || scope.Stopped() {
+				mutex.Lock()
+				skipped = append(skipped, index)
+				mutex.Unlock()
 
-			// Executa o teste usando a função reutilizável
-			testStart := time.Now()
-			result := BatchResult{
-				TestName: testCase.Name,
-				Duration: 0,
+				return
 			}
 
-			fmt.Printf("🔄 [%d/%d] Executando: %s\n", index+1, len(testCases), testCase.Name)
-
-			resp, err := b.request.Request(testCase)
-			result.Duration = time.Since(testStart)
-
-			if err != nil {
-				result.Success = false
-				result.Error = err.Error()
-				fmt.Printf("❌ [%d/%d] %s: FALHOU (%v)\n", index+1, len(testCases), testCase.Name, err)
-			} else {
-				defer func() {
-					err := resp.Body.Close()
-					if err != nil {
-						fmt.Printf("Erro ao fechar resposta: %v\n", err)
-					}
-				}()
-
-				result.Status = resp.StatusCode
-
-				body, _ := io.ReadAll(resp.Body)
-				result.Response = string(body)
-
-				if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-					result.Success = true
-					fmt.Printf("✅ [%d/%d] %s: OK (Status: %d, %v)\n",
-						index+1, len(testCases), testCase.Name, resp.StatusCode, result.Duration)
-				} else {
-					result.Success = false
-					result.Error = fmt.Sprintf("Status HTTP %d", resp.StatusCode)
-					fmt.Printf("⚠️  [%d/%d] %s: Status %d (%v)\n",
-						index+1, len(testCases), testCase.Name, resp.StatusCode, result.Duration)
-				}
-			}
+			result := b.publish(scope.Work, index, len(publications), item, engine, allowUnroutable, retry,
+				output.Printf)
 
 			// Thread-safe append
 			mutex.Lock()
 			results = append(results, result)
 			mutex.Unlock()
-		}(i, tc)
+		}(i, item)
 	}
 
 	wg.Wait()
 
 	totalTime := time.Since(startTime)
-	fmt.Printf("⏱️  Execução concluída em %v\n", totalTime)
+	output.Printf("⏱️  Execução concluída em %v\n", totalTime)
+
+	sort.Ints(skipped)
+
+	notRun := make([]string, 0, len(skipped))
+	for _, index := range skipped {
+		notRun = append(notRun, publications[index].label())
+	}
 
-	return results
+	return results, notRun
 }
 
-func calculateTotalTime(results []BatchResult) time.Duration {
-	var total time.Duration
+// publish executa um caso de teste e monta o resultado; logf recebe as
+// mensagens de progresso e pode descartá-las, como no modo de carga
+func (b *Batch) publish(
+	ctx context.Context,
+	index, total int,
+	item publication,
+	engine *tmpl.Engine,
+	allowUnroutable bool,
+	retry request.RetryPolicy,
+	logf func(format string, args ...any),
+) BatchResult {
+	// Executa o teste usando a função reutilizável
+	testCase := item.testCase
+	name := item.label()
+	testStart := time.Now()
+	result := BatchResult{
+		TestName: testCase.Name,
+		Row:      item.row,
+		Started:  testStart,
+		Duration: 0,
+	}
+
+	logf("🔄 [%d/%d] Executando: %s\n", index+1, total, name)
+
+	msg, err := engine.RenderTestCase(testCase)
+
+	// Com expect.purge, mensagens antigas da fila não contam para esta publicação
+	if err == nil {
+		err = expect.Prepare(ctx, b.request, msg)
+	}
+
+	var resp *http.Response
+	if err == nil {
+		resp, result.Attempts, err = request.Publish(ctx, b.request, msg, retry,
+			func(attempt int, reason string, wait time.Duration) {
+				logf("🔁 [%d/%d] %s: tentativa %d/%d falhou (%s), nova tentativa em %v\n",
+					index+1, total, name, attempt, retry.MaxAttempts, reason, wait.Round(time.Millisecond))
+			})
+	}
+
+	result.Duration = time.Since(testStart)
+
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		logf("❌ [%d/%d] %s: FALHOU (%v)\n", index+1, total, name, err)
+	} else {
+		defer func() {
+			err := resp.Body.Close()
+			if err != nil {
+				logf("Erro ao fechar resposta: %v\n", err)
+			}
+		}()
+
+		result.Status = resp.StatusCode
+
+		body, _ := io.ReadAll(resp.Body)
+		result.Response = string(body)
+
+		accepted := resp.StatusCode >= 200 && resp.StatusCode < 300
+
+		// A API responde 200 mesmo quando nenhuma fila recebe a mensagem
+		var parseErr error
+		if accepted {
+			var publish request.PublishResult
+			publish, parseErr = request.ParseResult(body)
+			result.Routed = publish.Routed
+		}
+
+		switch {
+		case accepted && parseErr != nil:
+			result.Success = false
+			result.Error = parseErr.Error()
+			logf("⚠️  [%d/%d] %s: %v\n", index+1, total, name, parseErr)
+		case accepted && !result.Routed && !allowUnroutable:
+			result.Success = false
+			result.Error = "mensagem não roteada para nenhuma fila (verifique route key e exchange)"
+			logf("❌ [%d/%d] %s: NÃO ROTEADA (Status: %d, %v)\n",
+				index+1, total, name, resp.StatusCode, result.Duration)
+		case accepted:
+			result.Success = true
+			logf("✅ [%d/%d] %s: OK (Status: %d, Roteada: %t, %v)\n",
+				index+1, total, name, resp.StatusCode, result.Routed, result.Duration)
+
+			if len(msg.Capture) > 0 {
+				capture(&result, msg, engine, index, total, logf)
+			}
+
+			if result.Success && msg.Expect != nil {
+				b.checkExpect(ctx, &result, engine, msg, index, total, logf)
+			}
+		default:
+			result.Success = false
+			result.Error = fmt.Sprintf("Status HTTP %d", resp.StatusCode)
+			logf("⚠️  [%d/%d] %s: Status %d (%v)\n",
+				index+1, total, name, resp.StatusCode, result.Duration)
+		}
+	}
+
+	return result
+}
+
+// capture guarda as variáveis do bloco capture para os testes seguintes e marca o
+// resultado como falha quando algum caminho não é encontrado
+func capture(
+	result *BatchResult,
+	msg rabbix.TestCase,
+	engine *tmpl.Engine,
+	index, total int,
+	logf func(format string, args ...any),
+) {
+	captured, err := engine.Capture(msg.Capture, tmpl.SourcesOf(msg))
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		logf("❌ [%d/%d] %s: CAPTURE FALHOU (%v)\n", index+1, total, result.label(), err)
+
+		return
+	}
+
+	result.Captured = captured
+
+	for _, name := range rabbix.CaptureNames(msg.Capture) {
+		data, _ := json.Marshal(captured[name])
+		logf("📌 [%d/%d] %s: %s = %s\n", index+1, total, result.label(), name, string(data))
+	}
+}
+
+type failureGroup struct {
+	result BatchResult
+	count  int
+}
+
+// groupFailures agrupa falhas repetidas do mesmo teste, comuns no modo de carga,
+// mantendo a ordem da primeira ocorrência
+func groupFailures(results []BatchResult) []*failureGroup {
+	var groups []*failureGroup
+
+	index := map[string]*failureGroup{}
 
 	for _, result := range results {
-		total += result.Duration
+		if result.Success {
+			continue
+		}
+
+		key := result.label() + "\x00" + result.Error
+		if group, ok := index[key]; ok {
+			group.count++
+			continue
+		}
+
+		group := &failureGroup{result: result, count: 1}
+		index[key] = group
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+func (r BatchResult) label() string {
+	return label(r.TestName, r.Row)
+}
+
+// failedRows lista as linhas do --data com alguma publicação com falha
+func failedRows(results []BatchResult) []string {
+	var rows []int
+
+	seen := map[int]bool{}
+
+	for _, result := range results {
+		if !result.Success && result.Row > 0 && !seen[result.Row] {
+			seen[result.Row] = true
+			rows = append(rows, result.Row)
+		}
+	}
+
+	sort.Ints(rows)
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, strconv.Itoa(row))
+	}
+
+	return lines
+}
+
+// assertionFailed indica que a publicação teve sucesso, mas o expect falhou
+func (r BatchResult) assertionFailed() bool {
+	for _, assertion := range r.Assertions {
+		if !assertion.Passed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exitError define o código de saída do lote: falhas de publicação têm
+// precedência sobre falhas de asserção, e testes pulados contam como falha parcial
+func exitError(success, failed, assertionFailed, skipped int) error {
+	total := success + failed + skipped
+
+	switch {
+	case failed > assertionFailed && success == 0 && assertionFailed == 0 && skipped == 0:
+		return exitcode.New(exitcode.Publish, "nenhum dos %d teste(s) teve sucesso", total)
+	case failed > assertionFailed || skipped > 0:
+		return exitcode.New(exitcode.Partial, "%d de %d teste(s) falharam ou foram pulados", failed+skipped, total)
+	case assertionFailed > 0:
+		return exitcode.New(exitcode.Assertion, "%d de %d teste(s) com asserções do expect falhando", assertionFailed, total)
+	default:
+		return nil
+	}
+}
+
+// checkExpect avalia o bloco expect de um teste publicado e marca o resultado como
+// falha quando alguma asserção não passa; logf recebe as mensagens, como no publish
+func (b *Batch) checkExpect(
+	ctx context.Context,
+	result *BatchResult,
+	engine *tmpl.Engine,
+	tc rabbix.TestCase,
+	index, total int,
+	logf func(format string, args ...any),
+) {
+	var check expect.Result
+
+	// Os valores esperados podem usar as variáveis capturadas da própria mensagem
+	rendered, err := expect.Render(*tc.Expect, engine)
+	if err == nil {
+		tc.Expect = &rendered
+		check, err = expect.Check(ctx, b.request, tc)
+	}
+
+	// Como no run, a mensagem foi publicada e o erro conta como falha de asserção
+	if err != nil {
+		result.Success = false
+		result.Error = "asserções do expect não avaliadas"
+		result.Assertions = []expect.Assertion{{Name: "expect", Message: err.Error()}}
+		logf("❌ [%d/%d] %s: EXPECT FALHOU (%v)\n", index+1, total, result.label(), err)
+
+		return
+	}
+
+	result.Assertions = check.Assertions
+
+	if !check.Passed() {
+		result.Success = false
+		result.Error = fmt.Sprintf("%d de %d asserção(ões) falharam", check.Failed(), len(check.Assertions))
+		logf("❌ [%d/%d] %s: EXPECT FALHOU (%s)\n", index+1, total, result.label(), result.Error)
+
+		return
 	}
 
-	return total
+	logf("🔎 [%d/%d] %s: %d asserção(ões) OK\n", index+1, total, result.label(), len(check.Assertions))
 }
@@ -0,0 +1,95 @@
+package batch
+
+import (
+	"testing"
+
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/expect"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+)
+
+func TestExitError(t *testing.T) {
+	tests := []struct {
+		name                                      string
+		success, failed, assertionFailed, skipped int
+		want                                      int
+	}{
+		{name: "todos com sucesso", success: 3, want: exitcode.Success},
+		{name: "todos falharam", failed: 3, want: exitcode.Publish},
+		{name: "parte falhou", success: 2, failed: 1, want: exitcode.Partial},
+		{name: "pulados contam como parcial", success: 2, skipped: 1, want: exitcode.Partial},
+		{name: "todos pulados", skipped: 2, want: exitcode.Partial},
+		{name: "só asserções falharam", success: 1, failed: 2, assertionFailed: 2, want: exitcode.Assertion},
+		{name: "todas as asserções falharam", failed: 2, assertionFailed: 2, want: exitcode.Assertion},
+		{
+			name:    "publicação tem precedência sobre asserção",
+			success: 1, failed: 2, assertionFailed: 1,
+			want: exitcode.Partial,
+		},
+		{name: "asserção com pulados", failed: 1, assertionFailed: 1, skipped: 1, want: exitcode.Partial},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := exitError(tt.success, tt.failed, tt.assertionFailed, tt.skipped)
+			if got := exitcode.Code(err); got != tt.want {
+				t.Errorf("exitError = %d (%v), esperado %d", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssertionFailed(t *testing.T) {
+	tests := []struct {
+		name       string
+		assertions []expect.Assertion
+		want       bool
+	}{
+		{name: "sem expect"},
+		{name: "todas passaram", assertions: []expect.Assertion{{Passed: true}, {Passed: true}}},
+		{name: "uma falhou", assertions: []expect.Assertion{{Passed: true}, {Passed: false}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (BatchResult{Assertions: tt.assertions}).assertionFailed(); got != tt.want {
+				t.Errorf("assertionFailed = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSharedExpectQueue(t *testing.T) {
+	observe := func(name, vhost, queue string) publication {
+		return publication{testCase: rabbix.TestCase{
+			Name:   name,
+			Vhost:  vhost,
+			Expect: &rabbix.Expect{Queue: queue},
+		}}
+	}
+
+	distinct := []publication{
+		observe("criar", "/", "pedidos.criados"),
+		observe("pagar", "/", "pedidos.pagos"),
+		observe("criar-loja", "/loja", "pedidos.criados"),
+		{testCase: rabbix.TestCase{Name: "sem-expect"}},
+	}
+
+	if queue, shared := sharedExpectQueue(distinct); shared {
+		t.Fatalf("sharedExpectQueue = %q em filas distintas", queue)
+	}
+
+	// O mesmo teste em várias linhas do --data também observa a mesma fila
+	rows := append(distinct, observe("criar", "/", "pedidos.criados"))
+	if queue, shared := sharedExpectQueue(rows); !shared || queue != "pedidos.criados" {
+		t.Errorf("sharedExpectQueue = %q, %v; esperado pedidos.criados", queue, shared)
+	}
+
+	// O vhost do expect tem precedência sobre o da publicação
+	explicit := observe("criar", "/", "pedidos.criados")
+	explicit.testCase.Expect.Vhost = "/loja"
+
+	if _, shared := sharedExpectQueue(append(distinct, explicit)); !shared {
+		t.Error("sharedExpectQueue deveria considerar o vhost do expect")
+	}
+}
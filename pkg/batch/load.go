@@ -0,0 +1,287 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/interrupt"
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/request"
+	"github.com/maxwelbm/rabbix/pkg/tmpl"
+)
+
+const (
+	// progressInterval é o intervalo entre as linhas de progresso do modo de carga
+	progressInterval = time.Second
+	// maxWait limita cada espera por fichas, para que a taxa da rampa seja reavaliada
+	maxWait = 10 * time.Millisecond
+)
+
+// LoadOptions configura o modo de carga do batch
+type LoadOptions struct {
+	// Rate é a taxa alvo em mensagens por segundo (0 = sem limite)
+	Rate float64
+	// Duration encerra a carga após o tempo informado (0 = sem limite de tempo)
+	Duration time.Duration
+	// RampUp aumenta a taxa linearmente de zero até Rate nesse intervalo
+	RampUp time.Duration
+	// Total encerra a carga após a quantidade de mensagens informada (0 = sem limite)
+	Total int
+	// Concurrency é o número de publicações simultâneas
+	Concurrency int
+
+	AllowUnroutable bool
+	Retry           request.RetryPolicy
+}
+
+// Enabled indica se alguma opção do modo de carga foi informada
+func (o LoadOptions) Enabled() bool {
+	return o.Rate > 0 || o.Duration > 0 || o.Total > 0
+}
+
+// Validate verifica a concorrência, usada também fora do modo de carga, e se a
+// combinação de opções permite encerrar a carga
+func (o LoadOptions) Validate() error {
+	switch {
+	case o.Concurrency < 1:
+		return fmt.Errorf("--concurrency deve ser maior que zero")
+	case o.Rate < 0 || o.Duration < 0 || o.RampUp < 0 || o.Total < 0:
+		return fmt.Errorf("--rate, --duration, --ramp-up e --total não podem ser negativos")
+	case o.RampUp > 0 && o.Rate == 0:
+		return fmt.Errorf("--ramp-up exige uma taxa alvo em --rate")
+	case o.Rate > 0 && o.Duration == 0 && o.Total == 0:
+		return fmt.Errorf("--rate exige --duration ou --total para encerrar a carga")
+	case o.Duration > 0 && o.RampUp > o.Duration:
+		return fmt.Errorf("--ramp-up (%v) maior que --duration (%v)", o.RampUp, o.Duration)
+	}
+
+	return nil
+}
+
+// tokenBucket libera publicações na taxa alvo. As fichas acumulam até o
+// limite de burst, o que compensa a imprecisão do time.Sleep sem estourar a taxa
+type tokenBucket struct {
+	rate   float64
+	rampUp time.Duration
+	burst  float64
+	start  time.Time
+	last   time.Time
+	tokens float64
+}
+
+func newTokenBucket(rate float64, rampUp time.Duration) *tokenBucket {
+	now := time.Now()
+
+	return &tokenBucket{
+		rate:   rate,
+		rampUp: rampUp,
+		// até 100ms de mensagens acumuladas
+		burst:  math.Max(1, rate/10),
+		start:  now,
+		last:   now,
+		tokens: 1,
+	}
+}
+
+// currentRate aplica a rampa linear; o mínimo de 1 msg/s evita esperas
+// infinitas no início da rampa
+func (t *tokenBucket) currentRate(now time.Time) float64 {
+	elapsed := now.Sub(t.start)
+	if t.rampUp <= 0 || elapsed >= t.rampUp {
+		return t.rate
+	}
+
+	return math.Max(t.rate*float64(elapsed)/float64(t.rampUp), math.Min(1, t.rate))
+}
+
+// Wait bloqueia até haver uma ficha disponível ou o contexto ser cancelado,
+// retornando false no cancelamento. Não é seguro para uso concorrente: apenas o
+// despachante do modo de carga consome fichas
+func (t *tokenBucket) Wait(ctx context.Context) bool {
+	for {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		now := time.Now()
+		rate := t.currentRate(now)
+
+		t.tokens = math.Min(t.burst, t.tokens+now.Sub(t.last).Seconds()*rate)
+		t.last = now
+
+		if t.tokens >= 1 {
+			t.tokens--
+			return true
+		}
+
+		time.Sleep(min(time.Duration((1-t.tokens)/rate*float64(time.Second)), maxWait))
+	}
+}
+
+// executeLoad publica as publicações do lote em rodízio na taxa alvo até atingir a
+// duração, o total de mensagens ou uma interrupção, exibindo o progresso periodicamente.
+// O engine é compartilhado entre as goroutines para que {{seq}} avance a cada mensagem
+func (b *Batch) executeLoad(
+	scope *interrupt.Scope,
+	publications []publication,
+	engine *tmpl.Engine,
+	options LoadOptions,
+) []BatchResult {
+	var (
+		results   []BatchResult
+		mutex     sync.Mutex
+		wg        sync.WaitGroup
+		completed atomic.Int64
+		failures  atomic.Int64
+	)
+
+	jobs := make(chan int, options.Concurrency)
+
+	discard := func(string, ...any) {}
+
+	for range options.Concurrency {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for seq := range jobs {
+				item := publications[seq%len(publications)]
+				result := b.publish(scope.Work, seq, options.Total, item, engine, options.AllowUnroutable,
+					options.Retry, discard)
+
+				if !result.Success {
+					failures.Add(1)
+				}
+
+				completed.Add(1)
+
+				// O corpo da resposta não é guardado no modo de carga, para que
+				// execuções longas com --duration não acumulem memória
+				result.Response = ""
+
+				mutex.Lock()
+				results = append(results, result)
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	var bucket *tokenBucket
+	if options.Rate > 0 {
+		bucket = newTokenBucket(options.Rate, options.RampUp)
+	}
+
+	startTime := time.Now()
+	done := make(chan struct{})
+
+	var progressWG sync.WaitGroup
+
+	progressWG.Add(1)
+
+	go func() {
+		defer progressWG.Done()
+
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+
+		last := int64(0)
+		lastTime := startTime
+
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				current := completed.Load()
+				interval := float64(current-last) / now.Sub(lastTime).Seconds()
+				last, lastTime = current, now
+
+				printProgress(now.Sub(startTime), current, failures.Load(), interval, bucket, options)
+			}
+		}
+	}()
+
+	// Despachante: libera uma mensagem por ficha até atingir o limite ou ser interrompido
+dispatch:
+	for seq := 0; ; seq++ {
+		if options.Total > 0 && seq >= options.Total {
+			break
+		}
+
+		if bucket != nil && !bucket.Wait(scope.Schedule) {
+			break
+		}
+
+		if options.Duration > 0 && time.Since(startTime) >= options.Duration {
+			break
+		}
+
+		select {
+		case jobs <- seq:
+		case <-scope.Schedule.Done():
+			break dispatch
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+	close(done)
+	progressWG.Wait()
+
+	elapsed := time.Since(startTime)
+	total := completed.Load()
+
+	output.Printf("⏱️  Carga concluída em %v: %d mensagem(ns), média de %.1f msg/s\n",
+		elapsed.Round(time.Millisecond), total, float64(total)/elapsed.Seconds())
+
+	return results
+}
+
+func printProgress(
+	elapsed time.Duration,
+	completed, failed int64,
+	interval float64,
+	bucket *tokenBucket,
+	options LoadOptions,
+) {
+	sent := fmt.Sprintf("%d", completed)
+	if options.Total > 0 {
+		sent = fmt.Sprintf("%d/%d", completed, options.Total)
+	}
+
+	target := "sem limite"
+	if bucket != nil {
+		target = fmt.Sprintf("%.1f msg/s", bucket.currentRate(time.Now()))
+	}
+
+	output.Printf("📈 [%v] Enviadas: %s | Falhas: %d | Taxa: %.1f msg/s (alvo: %s) | Média: %.1f msg/s\n",
+		elapsed.Round(time.Second), sent, failed, interval, target, float64(completed)/elapsed.Seconds())
+}
+
+func printLoadHeader(options LoadOptions) {
+	rate := "sem limite"
+	if options.Rate > 0 {
+		rate = fmt.Sprintf("%.1f msg/s", options.Rate)
+	}
+
+	output.Printf("📈 Modo de carga | Taxa alvo: %s | Concorrência: %d\n", rate, options.Concurrency)
+
+	if options.Duration > 0 {
+		output.Printf("⏳ Duração: %v", options.Duration)
+
+		if options.RampUp > 0 {
+			output.Printf(" | Rampa: %v", options.RampUp)
+		}
+
+		output.Println()
+	}
+
+	if options.Total > 0 {
+		output.Printf("🔢 Total: %d mensagem(ns)\n", options.Total)
+	}
+}
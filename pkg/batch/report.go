@@ -0,0 +1,332 @@
+package batch
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/expect"
+	"github.com/maxwelbm/rabbix/pkg/output"
+)
+
+const (
+	// ReportJUnit gera um XML no formato JUnit, lido pela maioria das ferramentas de CI
+	ReportJUnit = "junit"
+	// ReportJSON gera um JSON com o resumo e o resultado de cada teste
+	ReportJSON = "json"
+)
+
+// Report é um relatório solicitado por --report no formato 'tipo=caminho'
+type Report struct {
+	Format string
+	Path   string
+}
+
+// ParseReports interpreta os valores de --report
+func ParseReports(specs []string) ([]Report, error) {
+	var reports []Report
+
+	for _, spec := range specs {
+		format, path, ok := strings.Cut(spec, "=")
+		format = strings.ToLower(strings.TrimSpace(format))
+		path = strings.TrimSpace(path)
+
+		if !ok || path == "" {
+			return nil, fmt.Errorf("relatório inválido '%s' (esperado 'junit=arquivo.xml' ou 'json=arquivo.json')", spec)
+		}
+
+		if format != ReportJUnit && format != ReportJSON {
+			return nil, fmt.Errorf("formato de relatório desconhecido '%s' (use '%s' ou '%s')",
+				format, ReportJUnit, ReportJSON)
+		}
+
+		reports = append(reports, Report{Format: format, Path: path})
+	}
+
+	return reports, nil
+}
+
+// Execution reúne os resultados do lote usados no resumo e nos relatórios
+type Execution struct {
+	Results []BatchResult
+	Elapsed time.Duration
+	// NotRun lista os testes que não chegaram a ser executados por uma interrupção
+	NotRun []string
+	// Interrupted é o motivo da interrupção, vazio quando o lote terminou
+	Interrupted string
+}
+
+// Write grava o relatório com os resultados do lote
+func (r Report) Write(execution Execution) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch r.Format {
+	case ReportJUnit:
+		data, err = junitReport(execution)
+	default:
+		data, err = jsonReport(execution)
+	}
+
+	if err != nil {
+		return fmt.Errorf("erro ao gerar relatório %s: %w", r.Format, err)
+	}
+
+	if dir := filepath.Dir(r.Path); dir != "" {
+		_ = os.MkdirAll(dir, os.ModePerm)
+	}
+
+	if err := os.WriteFile(r.Path, data, 0644); err != nil {
+		return fmt.Errorf("erro ao salvar relatório %s: %w", r.Format, err)
+	}
+
+	return nil
+}
+
+// Summary é o resultado do lote no relatório JSON e na saída estruturada
+type Summary struct {
+	GeneratedAt time.Time    `json:"generated_at"`
+	Total       int          `json:"total"`
+	Passed      int          `json:"passed"`
+	Failed      int          `json:"failed"`
+	DurationMs  float64      `json:"duration_ms"`
+	Interrupted string       `json:"interrupted,omitempty"`
+	NotRun      []string     `json:"not_run,omitempty"`
+	Stats       Stats        `json:"stats"`
+	Tests       []Stats      `json:"tests"`
+	Results     []jsonResult `json:"results"`
+}
+
+type jsonResult struct {
+	Name       string             `json:"name"`
+	Row        int                `json:"row,omitempty"`
+	Status     string             `json:"status"`
+	DurationMs float64            `json:"duration_ms"`
+	HTTPStatus int                `json:"http_status,omitempty"`
+	Routed     bool               `json:"routed"`
+	Attempts   int                `json:"attempts"`
+	Response   string             `json:"response,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	Captured   map[string]any     `json:"captured,omitempty"`
+	Assertions []expect.Assertion `json:"assertions,omitempty"`
+}
+
+func jsonReport(execution Execution) ([]byte, error) {
+	return json.MarshalIndent(newSummary(execution), "", "  ")
+}
+
+func newSummary(execution Execution) Summary {
+	summary := Summary{
+		GeneratedAt: time.Now(),
+		Total:       len(execution.Results),
+		DurationMs:  output.Milliseconds(execution.Elapsed),
+		Interrupted: execution.Interrupted,
+		NotRun:      execution.NotRun,
+		Stats:       computeStats("", execution.Results, execution.Elapsed),
+		Tests:       statsByTest(execution.Results),
+		Results:     []jsonResult{},
+	}
+
+	for _, result := range execution.Results {
+		status := "passed"
+		if result.Success {
+			summary.Passed++
+		} else {
+			status = "failed"
+			summary.Failed++
+		}
+
+		summary.Results = append(summary.Results, jsonResult{
+			Name:       result.TestName,
+			Row:        result.Row,
+			Status:     status,
+			DurationMs: output.Milliseconds(result.Duration),
+			HTTPStatus: result.Status,
+			Routed:     result.Routed,
+			Attempts:   result.Attempts,
+			Response:   result.Response,
+			Error:      result.Error,
+			Captured:   result.Captured,
+			Assertions: result.Assertions,
+		})
+	}
+
+	return summary
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	Cases      []junitCase     `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func junitReport(execution Execution) ([]byte, error) {
+	results := execution.Results
+
+	suite := junitSuite{
+		Name:      "rabbix.batch",
+		Tests:     len(results) + len(execution.NotRun),
+		Skipped:   len(execution.NotRun),
+		Time:      seconds(execution.Elapsed),
+		Timestamp: time.Now().Format("2006-01-02T15:04:05"),
+	}
+
+	// As estatísticas vão como properties, geral e por teste (prefixo "<teste>.")
+	suite.Properties = statsProperties("", computeStats("", results, execution.Elapsed))
+	for _, stats := range statsByTest(results) {
+		suite.Properties = append(suite.Properties, statsProperties(stats.Name+".", stats)...)
+	}
+
+	if execution.Interrupted != "" {
+		suite.Properties = append(suite.Properties, junitProperty{Name: "interrupted", Value: execution.Interrupted})
+	}
+
+	for _, result := range results {
+		testCase := junitCase{
+			Name:      result.label(),
+			ClassName: "rabbix",
+			Time:      seconds(result.Duration),
+			SystemOut: result.Response,
+		}
+
+		if !result.Success {
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message: result.Error,
+				Type:    failureType(result),
+				Text:    failureDetails(result),
+			}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	// Testes não executados por uma interrupção aparecem como ignorados
+	for _, name := range execution.NotRun {
+		suite.Cases = append(suite.Cases, junitCase{
+			Name:      name,
+			ClassName: "rabbix",
+			Time:      seconds(0),
+			Skipped:   &junitSkipped{Message: execution.Interrupted},
+		})
+	}
+
+	suites := junitSuites{
+		Name:     "rabbix",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitSuite{suite},
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+func statsProperties(prefix string, stats Stats) []junitProperty {
+	format := func(value float64) string { return strconv.FormatFloat(value, 'f', -1, 64) }
+
+	return []junitProperty{
+		{Name: prefix + "count", Value: strconv.Itoa(stats.Count)},
+		{Name: prefix + "retried", Value: strconv.Itoa(stats.Retried)},
+		{Name: prefix + "attempts", Value: strconv.Itoa(stats.Attempts)},
+		{Name: prefix + "wall_time_ms", Value: format(stats.WallTimeMs)},
+		{Name: prefix + "msgs_per_sec", Value: format(stats.Throughput)},
+		{Name: prefix + "latency_min_ms", Value: format(stats.Latency.Min)},
+		{Name: prefix + "latency_mean_ms", Value: format(stats.Latency.Mean)},
+		{Name: prefix + "latency_p50_ms", Value: format(stats.Latency.P50)},
+		{Name: prefix + "latency_p90_ms", Value: format(stats.Latency.P90)},
+		{Name: prefix + "latency_p99_ms", Value: format(stats.Latency.P99)},
+		{Name: prefix + "latency_max_ms", Value: format(stats.Latency.Max)},
+		{Name: prefix + "status_codes", Value: formatStatusCodes(stats.StatusCodes)},
+	}
+}
+
+// failureType classifica a falha para as ferramentas de CI
+func failureType(result BatchResult) string {
+	switch {
+	case result.assertionFailed():
+		return "AssertionFailure"
+	case result.Status >= 200 && result.Status < 300 && !result.Routed:
+		return "Unroutable"
+	case result.Status != 0:
+		return "PublishFailure"
+	default:
+		return "Error"
+	}
+}
+
+func failureDetails(result BatchResult) string {
+	lines := []string{result.Error}
+
+	if result.Status != 0 {
+		lines = append(lines, fmt.Sprintf("Status HTTP: %d | Roteada: %t", result.Status, result.Routed))
+	}
+
+	if result.Attempts > 1 {
+		lines = append(lines, fmt.Sprintf("Tentativas: %d", result.Attempts))
+	}
+
+	for _, assertion := range result.Assertions {
+		if !assertion.Passed {
+			lines = append(lines, assertion.Detail())
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
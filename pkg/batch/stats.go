@@ -0,0 +1,225 @@
+package batch
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/output"
+)
+
+// statusError agrupa as publicações que falharam antes de obter um status HTTP
+const statusError = "erro"
+
+// Latency resume a distribuição das latências de publicação, em milissegundos
+type Latency struct {
+	Min  float64 `json:"min_ms"`
+	Mean float64 `json:"mean_ms"`
+	P50  float64 `json:"p50_ms"`
+	P90  float64 `json:"p90_ms"`
+	P99  float64 `json:"p99_ms"`
+	Max  float64 `json:"max_ms"`
+}
+
+// Stats são as estatísticas de um conjunto de resultados, de um teste ou do lote
+type Stats struct {
+	Name        string         `json:"name,omitempty"`
+	Count       int            `json:"count"`
+	Success     int            `json:"success"`
+	Failed      int            `json:"failed"`
+	Retried     int            `json:"retried"`
+	Attempts    int            `json:"attempts"`
+	WallTimeMs  float64        `json:"wall_time_ms"`
+	Throughput  float64        `json:"msgs_per_sec"`
+	Latency     Latency        `json:"latency"`
+	StatusCodes map[string]int `json:"status_codes"`
+}
+
+// computeStats calcula as estatísticas dos resultados. Sem wall time informado,
+// ele é medido do início da primeira publicação ao fim da última
+func computeStats(name string, results []BatchResult, wallTime time.Duration) Stats {
+	stats := Stats{
+		Name:        name,
+		Count:       len(results),
+		StatusCodes: map[string]int{},
+	}
+
+	if len(results) == 0 {
+		return stats
+	}
+
+	durations := make([]time.Duration, 0, len(results))
+
+	var (
+		first, last time.Time
+		sum         time.Duration
+	)
+
+	for _, result := range results {
+		if result.Success {
+			stats.Success++
+		} else {
+			stats.Failed++
+		}
+
+		stats.StatusCodes[statusKey(result.Status)]++
+
+		// Publicações que falharam antes de tentar, como erro de template, contam como uma
+		stats.Attempts += max(result.Attempts, 1)
+		if result.Attempts > 1 {
+			stats.Retried++
+		}
+
+		durations = append(durations, result.Duration)
+		sum += result.Duration
+
+		end := result.Started.Add(result.Duration)
+		if first.IsZero() || result.Started.Before(first) {
+			first = result.Started
+		}
+
+		if end.After(last) {
+			last = end
+		}
+	}
+
+	if wallTime <= 0 {
+		wallTime = last.Sub(first)
+	}
+
+	stats.WallTimeMs = output.Milliseconds(wallTime)
+	if wallTime > 0 {
+		stats.Throughput = round(float64(len(results)) / wallTime.Seconds())
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	stats.Latency = Latency{
+		Min:  output.Milliseconds(durations[0]),
+		Mean: output.Milliseconds(sum / time.Duration(len(durations))),
+		P50:  output.Milliseconds(percentile(durations, 50)),
+		P90:  output.Milliseconds(percentile(durations, 90)),
+		P99:  output.Milliseconds(percentile(durations, 99)),
+		Max:  output.Milliseconds(durations[len(durations)-1]),
+	}
+
+	return stats
+}
+
+// statsByTest calcula as estatísticas de cada teste, na ordem da primeira execução
+func statsByTest(results []BatchResult) []Stats {
+	var names []string
+
+	grouped := map[string][]BatchResult{}
+
+	for _, result := range results {
+		if _, ok := grouped[result.TestName]; !ok {
+			names = append(names, result.TestName)
+		}
+
+		grouped[result.TestName] = append(grouped[result.TestName], result)
+	}
+
+	stats := make([]Stats, 0, len(names))
+	for _, name := range names {
+		stats = append(stats, computeStats(name, grouped[name], 0))
+	}
+
+	return stats
+}
+
+// percentile usa o método nearest-rank sobre as durações já ordenadas
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+
+	return sorted[max(rank, 1)-1]
+}
+
+func statusKey(status int) string {
+	if status == 0 {
+		return statusError
+	}
+
+	return strconv.Itoa(status)
+}
+
+// formatStatusCodes ordena os status para exibição, ex: "200=10 503=2 erro=1"
+func formatStatusCodes(codes map[string]int) string {
+	keys := make([]string, 0, len(codes))
+	for key := range codes {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%d", key, codes[key]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func printStats(overall Stats, tests []Stats) {
+	output.Printf("⏱️  Tempo total: %v | Vazão: %.1f msg/s\n",
+		time.Duration(overall.WallTimeMs*float64(time.Millisecond)).Round(time.Millisecond), overall.Throughput)
+	output.Printf("📉 Latência: %s\n", formatLatency(overall.Latency))
+	output.Printf("🔢 Status: %s\n", formatStatusCodes(overall.StatusCodes))
+
+	if overall.Retried > 0 {
+		output.Printf("🔁 Retentativas: %d publicação(ões) repetida(s), %d tentativa(s) no total\n",
+			overall.Retried, overall.Attempts)
+	}
+
+	if len(tests) < 2 {
+		return
+	}
+
+	output.Println("\n📋 Por teste:")
+
+	for _, stats := range tests {
+		output.Printf("  • %s: %d msg(s), %d ok | %.1f msg/s | %s | %s\n",
+			stats.Name, stats.Count, stats.Success, stats.Throughput,
+			formatLatency(stats.Latency), formatStatusCodes(stats.StatusCodes))
+	}
+}
+
+func formatLatency(latency Latency) string {
+	return fmt.Sprintf("min %.1fms | média %.1fms | p50 %.1fms | p90 %.1fms | p99 %.1fms | máx %.1fms",
+		latency.Min, latency.Mean, latency.P50, latency.P90, latency.P99, latency.Max)
+}
+
+// statsTable monta a tabela do --output table, com uma linha por teste e o total
+func statsTable(overall Stats, tests []Stats) *output.Table {
+	table := &output.Table{
+		Header: []string{"TESTE", "MSGS", "OK", "FALHAS", "MSG/S", "MIN", "MÉDIA", "P50", "P90", "P99", "MÁX", "STATUS"},
+	}
+
+	overall.Name = "TOTAL"
+
+	for _, stats := range append(append([]Stats{}, tests...), overall) {
+		table.Rows = append(table.Rows, []string{
+			stats.Name,
+			strconv.Itoa(stats.Count),
+			strconv.Itoa(stats.Success),
+			strconv.Itoa(stats.Failed),
+			fmt.Sprintf("%.1f", stats.Throughput),
+			fmt.Sprintf("%.1f", stats.Latency.Min),
+			fmt.Sprintf("%.1f", stats.Latency.Mean),
+			fmt.Sprintf("%.1f", stats.Latency.P50),
+			fmt.Sprintf("%.1f", stats.Latency.P90),
+			fmt.Sprintf("%.1f", stats.Latency.P99),
+			fmt.Sprintf("%.1f", stats.Latency.Max),
+			formatStatusCodes(stats.StatusCodes),
+		})
+	}
+
+	return table
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,112 @@
+package batch
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		durations := make([]time.Duration, len(values))
+		for i, value := range values {
+			durations[i] = time.Duration(value) * time.Millisecond
+		}
+
+		return durations
+	}
+
+	hundred := make([]int, 100)
+	for i := range hundred {
+		hundred[i] = i + 1
+	}
+
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{name: "um valor", sorted: ms(7), p: 99, want: 7 * time.Millisecond},
+		{name: "p0 é o mínimo", sorted: ms(1, 2, 3), p: 0, want: time.Millisecond},
+		{name: "p50 de quatro", sorted: ms(1, 2, 3, 4), p: 50, want: 2 * time.Millisecond},
+		{name: "p50 de cinco", sorted: ms(1, 2, 3, 4, 5), p: 50, want: 3 * time.Millisecond},
+		{name: "p90 de dez", sorted: ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), p: 90, want: 9 * time.Millisecond},
+		{name: "p99 de dez é o máximo", sorted: ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), p: 99, want: 10 * time.Millisecond},
+		{name: "p99 de cem", sorted: ms(hundred...), p: 99, want: 99 * time.Millisecond},
+		{name: "p100 é o máximo", sorted: ms(hundred...), p: 100, want: 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.p); got != tt.want {
+				t.Errorf("percentile(p%v) = %v, esperado %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeStats(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	results := []BatchResult{
+		{TestName: "a", Success: true, Status: 200, Attempts: 1, Started: start, Duration: 40 * time.Millisecond},
+		{TestName: "b", Success: false, Status: 503, Attempts: 3, Started: start, Duration: 10 * time.Millisecond},
+		{TestName: "a", Success: true, Status: 200, Attempts: 2, Started: start.Add(time.Second),
+			Duration: 20 * time.Millisecond},
+		{TestName: "a", Success: false, Started: start.Add(time.Second), Duration: 30 * time.Millisecond},
+	}
+
+	tests := []struct {
+		name     string
+		results  []BatchResult
+		wallTime time.Duration
+		want     Stats
+	}{
+		{
+			name: "sem resultados",
+			want: Stats{StatusCodes: map[string]int{}},
+		},
+		{
+			name:    "wall time medido pelos resultados",
+			results: results,
+			want: Stats{
+				Count: 4, Success: 2, Failed: 2, Retried: 2, Attempts: 7,
+				WallTimeMs: 1030, Throughput: 3.9,
+				Latency:     Latency{Min: 10, Mean: 25, P50: 20, P90: 40, P99: 40, Max: 40},
+				StatusCodes: map[string]int{"200": 2, "503": 1, statusError: 1},
+			},
+		},
+		{
+			name:     "wall time informado",
+			results:  results[:2],
+			wallTime: 2 * time.Second,
+			want: Stats{
+				Count: 2, Success: 1, Failed: 1, Retried: 1, Attempts: 4,
+				WallTimeMs: 2000, Throughput: 1,
+				Latency:     Latency{Min: 10, Mean: 25, P50: 10, P90: 40, P99: 40, Max: 40},
+				StatusCodes: map[string]int{"200": 1, "503": 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := computeStats("", tt.results, tt.wallTime); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("computeStats = %+v, esperado %+v", got, tt.want)
+			}
+		})
+	}
+
+	byTest := statsByTest(results)
+	if len(byTest) != 2 || byTest[0].Name != "a" || byTest[0].Count != 3 || byTest[1].Name != "b" {
+		t.Errorf("statsByTest = %+v, esperado a (3) e b (1) na ordem da primeira execução", byTest)
+	}
+}
+
+func TestFormatStatusCodes(t *testing.T) {
+	got := formatStatusCodes(map[string]int{"503": 2, statusError: 1, "200": 10})
+	if want := "200=10 503=2 erro=1"; got != want {
+		t.Errorf("formatStatusCodes = %q, esperado %q", got, want)
+	}
+}
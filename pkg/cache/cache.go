@@ -2,11 +2,12 @@ package cache
 
 import (
 	"encoding/json"
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/output"
 )
 
 func getCachePath() string {
@@ -87,10 +88,14 @@ func (c *Cache) SyncCacheWithFileSystem() {
 			if data, err := os.ReadFile(testPath); err == nil {
 				var testCase TestCase
 				if err := json.Unmarshal(data, &testCase); err == nil {
-					// Se já existe no cache, mantém as datas
+					// Se já existe no cache, mantém as datas; UpdatedAt só avança
+					// quando o arquivo foi modificado depois da última atualização
 					if existing, exists := cacheMap[fileName]; exists {
 						existing.RouteKey = testCase.RouteKey
-						existing.UpdatedAt = time.Now()
+						if info, err := file.Info(); err == nil && info.ModTime().After(existing.UpdatedAt) {
+							existing.UpdatedAt = info.ModTime()
+						}
+
 						newTests = append(newTests, existing)
 					} else {
 						// Novo teste encontrado - usa nome do arquivo, não o campo "name" do JSON
@@ -110,6 +115,30 @@ func (c *Cache) SyncCacheWithFileSystem() {
 	// Atualiza cache
 	cache.Tests = newTests
 	if err := saveCache(cache); err != nil {
-		fmt.Printf("❌ Erro ao salvar cache: %v\n", err)
+		output.Printf("❌ Erro ao salvar cache: %v\n", err)
+	}
+}
+
+// RenameCachedTest renomeia a entrada do cache mantendo as datas. Uma entrada
+// já existente com o novo nome (teste sobrescrito) é descartada
+func (c *Cache) RenameCachedTest(oldName, newName string) {
+	cache := loadCache()
+
+	var tests []CacheEntry
+
+	for _, entry := range cache.Tests {
+		switch entry.Name {
+		case newName:
+			continue
+		case oldName:
+			entry.Name = newName
+		}
+
+		tests = append(tests, entry)
+	}
+
+	cache.Tests = tests
+	if err := saveCache(cache); err != nil {
+		output.Printf("❌ Erro ao salvar cache: %v\n", err)
 	}
 }
@@ -1,8 +1,9 @@
 package cache
 
 import (
-	"fmt"
+	"time"
 
+	"github.com/maxwelbm/rabbix/pkg/output"
 	"github.com/maxwelbm/rabbix/pkg/sett"
 	"github.com/spf13/cobra"
 )
@@ -10,6 +11,7 @@ import (
 type CacheItf interface {
 	GetCachedTests() []string
 	SyncCacheWithFileSystem()
+	RenameCachedTest(oldName, newName string)
 	CmdCache() *cobra.Command
 }
 
@@ -43,18 +45,45 @@ func (c *Cache) cmdStats() *cobra.Command {
 	return &cobra.Command{
 		Use:   "stats",
 		Short: "Exibe estatísticas do cache",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			cache := loadCache()
-			fmt.Printf("📊 Cache Statistics:\n")
-			fmt.Printf("   Total tests: %d\n", len(cache.Tests))
-			fmt.Printf("   Cache version: %s\n", cache.Version)
-
-			if len(cache.Tests) > 0 {
-				fmt.Printf("   Tests available for autocomplete:\n")
-				for _, entry := range cache.Tests {
-					fmt.Printf("     • %s (route: %s)\n", entry.Name, entry.RouteKey)
-				}
+
+			stats := struct {
+				Total   int          `json:"total"`
+				Version string       `json:"version"`
+				Tests   []CacheEntry `json:"tests"`
+			}{
+				Total:   len(cache.Tests),
+				Version: cache.Version,
+				Tests:   cache.Tests,
+			}
+
+			if stats.Tests == nil {
+				stats.Tests = []CacheEntry{}
 			}
+
+			table := &output.Table{Header: []string{"NOME", "ROUTE KEY", "CRIADO EM", "ATUALIZADO EM"}}
+			for _, entry := range cache.Tests {
+				table.Rows = append(table.Rows, []string{
+					entry.Name,
+					entry.RouteKey,
+					entry.CreatedAt.Format(time.DateTime),
+					entry.UpdatedAt.Format(time.DateTime),
+				})
+			}
+
+			return output.Render(stats, table, func() {
+				output.Printf("📊 Cache Statistics:\n")
+				output.Printf("   Total tests: %d\n", len(cache.Tests))
+				output.Printf("   Cache version: %s\n", cache.Version)
+
+				if len(cache.Tests) > 0 {
+					output.Printf("   Tests available for autocomplete:\n")
+					for _, entry := range cache.Tests {
+						output.Printf("     • %s (route: %s)\n", entry.Name, entry.RouteKey)
+					}
+				}
+			})
 		},
 	}
 }
@@ -64,9 +93,9 @@ func (c *Cache) cmdSync() *cobra.Command {
 		Use:   "sync",
 		Short: "Sincroniza o cache com os arquivos de teste",
 		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("🔄 Sincronizando cache...")
+			output.Println("🔄 Sincronizando cache...")
 			c.SyncCacheWithFileSystem()
-			fmt.Println("✅ Cache sincronizado com sucesso.")
+			output.Println("✅ Cache sincronizado com sucesso.")
 		},
 	}
 }
@@ -82,9 +111,9 @@ func (c *Cache) cmdClear() *cobra.Command {
 			}
 
 			if err := saveCache(cache); err != nil {
-				fmt.Printf("❌ Erro ao limpar cache: %v\n", err)
+				output.Printf("❌ Erro ao limpar cache: %v\n", err)
 			} else {
-				fmt.Println("✅ Cache limpo com sucesso")
+				output.Println("✅ Cache limpo com sucesso")
 			}
 		},
 	}
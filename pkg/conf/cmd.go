@@ -10,6 +10,10 @@ var (
 	outputDir string
 	user      string
 	password  string
+	exchange  string
+	vhost     string
+	transport string
+	amqpURL   string
 )
 
 type Conf struct {
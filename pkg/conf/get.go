@@ -1,8 +1,9 @@
 package conf
 
 import (
-	"fmt"
+	"sort"
 
+	"github.com/maxwelbm/rabbix/pkg/output"
 	"github.com/spf13/cobra"
 )
 
@@ -10,12 +11,27 @@ func (c *Conf) CmdGet() *cobra.Command {
 	return &cobra.Command{
 		Use:   "get",
 		Short: "Exibe a configuração atual",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			settings := c.settings.LoadSettings()
-			fmt.Println("📦 Configuração atual:")
-			for k, v := range settings {
-				fmt.Printf("%s: %s\n", k, v)
+
+			keys := make([]string, 0, len(settings))
+			for k := range settings {
+				keys = append(keys, k)
 			}
+
+			sort.Strings(keys)
+
+			table := &output.Table{Header: []string{"CHAVE", "VALOR"}}
+			for _, k := range keys {
+				table.Rows = append(table.Rows, []string{k, settings[k]})
+			}
+
+			return output.Render(settings, table, func() {
+				output.Println("📦 Configuração atual:")
+				for _, k := range keys {
+					output.Printf("%s: %s\n", k, settings[k])
+				}
+			})
 		},
 	}
 }
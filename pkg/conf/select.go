@@ -2,11 +2,11 @@ package conf
 
 import (
 	"encoding/json"
-	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/maxwelbm/rabbix/pkg/output"
 	"github.com/spf13/cobra"
 )
 
@@ -33,13 +33,13 @@ func (c *Conf) CmdSelect() *cobra.Command {
 			if len(args) == 0 {
 				opts := listConfigFiles(baseDir)
 				if len(opts) == 0 {
-					fmt.Println("Nenhuma configuração encontrada. Informe um nome para criar uma nova, " +
+					output.Println("Nenhuma configuração encontrada. Informe um nome para criar uma nova, " +
 						"por exemplo: rabbix conf select minha.json")
 
 				} else {
-					fmt.Println("Informe o nome da configuração. Disponíveis:")
+					output.Println("Informe o nome da configuração. Disponíveis:")
 					for _, o := range opts {
-						fmt.Println("- " + o)
+						output.Println("- " + o)
 					}
 				}
 				return
@@ -61,7 +61,7 @@ func (c *Conf) CmdSelect() *cobra.Command {
 				if data, err := json.MarshalIndent(defaultCfg, "", "  "); err == nil {
 					_ = os.WriteFile(target, data, 0644)
 				}
-				fmt.Println("Criada nova configuração:", name)
+				output.Println("Criada nova configuração:", name)
 			}
 
 			// Updates settings.json with the selected file
@@ -75,7 +75,7 @@ func (c *Conf) CmdSelect() *cobra.Command {
 				_ = os.WriteFile(settPath, data, 0644)
 			}
 
-			fmt.Println("Configuração ativa atualizada para:", name)
+			output.Println("Configuração ativa atualizada para:", name)
 		},
 	}
 
@@ -0,0 +1,335 @@
+package dataset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maxwelbm/rabbix/pkg/jpath"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+)
+
+const (
+	// headersPrefix direciona a coluna para um header, ex: "headers.tenant"
+	headersPrefix = "headers."
+	// poolPrefix é opcional e desfaz ambiguidades, ex: "json_pool.route_key"
+	poolPrefix = "json_pool."
+	// routeKeyColumn sobrescreve a route key do teste
+	routeKeyColumn = "route_key"
+)
+
+type target int
+
+const (
+	targetPool target = iota
+	targetHeader
+	targetRouteKey
+)
+
+// column é o destino de uma coluna do CSV ou de um campo do JSON Lines
+type column struct {
+	name   string
+	target target
+	path   string
+	kind   string
+}
+
+type field struct {
+	column
+	value any
+}
+
+// Row é uma linha do arquivo de dados, que produz uma publicação
+type Row struct {
+	// Line é a linha no arquivo; no CSV o cabeçalho é a linha 1, como na planilha
+	Line   int
+	fields []field
+}
+
+// Load lê um arquivo .csv ou .jsonl (também .ndjson). Linhas em branco são ignoradas
+func Load(path string) ([]Row, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return parseCSV(data)
+	case ".jsonl", ".ndjson":
+		return parseJSONLines(data)
+	default:
+		return nil, fmt.Errorf("formato de '%s' não suportado (use .csv ou .jsonl)", path)
+	}
+}
+
+// Apply devolve uma cópia do caso de teste com os valores da linha aplicados.
+// Os valores também aceitam placeholders, avaliados na publicação
+func (r Row) Apply(tc rabbix.TestCase) (rabbix.TestCase, error) {
+	tc.JSONPool, _ = clone(tc.JSONPool).(map[string]any)
+	tc.Headers, _ = clone(tc.Headers).(map[string]any)
+
+	for _, f := range r.fields {
+		switch f.target {
+		case targetRouteKey:
+			tc.RouteKey, _ = f.value.(string)
+		case targetHeader:
+			if tc.Headers == nil {
+				tc.Headers = map[string]any{}
+			}
+
+			tc.Headers[f.path] = f.value
+		default:
+			if tc.Payload != nil {
+				return tc, fmt.Errorf("linha %d: a coluna '%s' exige um teste com json_pool, o teste '%s' usa 'payload'",
+					r.Line, f.name, tc.Name)
+			}
+
+			if tc.JSONPool == nil {
+				tc.JSONPool = map[string]any{}
+			}
+
+			if err := jpath.Set(tc.JSONPool, f.path, f.value); err != nil {
+				return tc, fmt.Errorf("linha %d: %w", r.Line, err)
+			}
+		}
+	}
+
+	return tc, nil
+}
+
+// parseCSV lê o cabeçalho e converte as células conforme o tipo da coluna.
+// Células vazias são ignoradas e mantêm o valor do caso de teste
+func parseCSV(data []byte) ([]Row, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("arquivo CSV vazio")
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("CSV inválido: %w", err)
+	}
+
+	columns := make([]column, len(header))
+	for i, name := range header {
+		if columns[i], err = parseColumn(name); err != nil {
+			return nil, fmt.Errorf("cabeçalho: %w", err)
+		}
+	}
+
+	var rows []Row
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("CSV inválido: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := Row{Line: line}
+
+		for i, cell := range record {
+			if cell == "" {
+				continue
+			}
+
+			value, err := convert(cell, columns[i].kind)
+			if err != nil {
+				return nil, fmt.Errorf("linha %d, coluna '%s': %w", line, columns[i].name, err)
+			}
+
+			row.fields = append(row.fields, field{column: columns[i], value: value})
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseJSONLines lê um objeto por linha; as chaves seguem as regras das colunas do CSV
+func parseJSONLines(data []byte) ([]Row, error) {
+	var rows []Row
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var object map[string]any
+		if err := decodeJSON(text, &object); err != nil {
+			return nil, fmt.Errorf("linha %d: JSON inválido (esperado um objeto por linha): %w", line, err)
+		}
+
+		row := Row{Line: line}
+
+		for _, name := range sortedKeys(object) {
+			col, err := parseColumn(name)
+			if err != nil {
+				return nil, fmt.Errorf("linha %d: %w", line, err)
+			}
+
+			value := object[name]
+			if text, ok := value.(string); ok {
+				if value, err = convert(text, col.kind); err != nil {
+					return nil, fmt.Errorf("linha %d, campo '%s': %w", line, name, err)
+				}
+			}
+
+			if _, ok := value.(string); col.target == targetRouteKey && !ok {
+				return nil, fmt.Errorf("linha %d: o campo '%s' deve ser texto", line, routeKeyColumn)
+			}
+
+			row.fields = append(row.fields, field{column: col, value: value})
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, scanner.Err()
+}
+
+// parseColumn interpreta nomes como "order.items[0].qty:int", "headers.tenant" e "route_key"
+func parseColumn(name string) (column, error) {
+	col := column{name: strings.TrimSpace(name)}
+	col.path = col.name
+
+	if i := strings.LastIndex(col.path, ":"); i >= 0 {
+		col.path, col.kind = col.path[:i], strings.ToLower(col.path[i+1:])
+
+		switch col.kind {
+		case "string", "int", "float", "bool", "json":
+		default:
+			return col, fmt.Errorf("tipo '%s' inválido na coluna '%s' (use string, int, float, bool ou json)",
+				col.kind, col.name)
+		}
+	}
+
+	switch {
+	case col.path == routeKeyColumn:
+		if col.kind != "" && col.kind != "string" {
+			return col, fmt.Errorf("a coluna '%s' deve ser do tipo string", routeKeyColumn)
+		}
+
+		col.target = targetRouteKey
+	case strings.HasPrefix(col.path, headersPrefix):
+		col.target = targetHeader
+		col.path = strings.TrimPrefix(col.path, headersPrefix)
+
+		if col.path == "" {
+			return col, fmt.Errorf("coluna '%s' sem o nome do header", col.name)
+		}
+	default:
+		col.path = strings.TrimPrefix(col.path, poolPrefix)
+
+		if _, err := jpath.Parse(col.path); err != nil {
+			return col, fmt.Errorf("coluna '%s': %w", col.name, err)
+		}
+	}
+
+	return col, nil
+}
+
+// convert interpreta o texto conforme o tipo da coluna; sem tipo o valor fica como texto
+func convert(text, kind string) (any, error) {
+	var (
+		value any
+		err   error
+	)
+
+	switch kind {
+	case "int":
+		value, err = strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	case "float":
+		value, err = strconv.ParseFloat(strings.TrimSpace(text), 64)
+	case "bool":
+		value, err = strconv.ParseBool(strings.TrimSpace(text))
+	case "json":
+		if err := decodeJSON(text, &value); err != nil {
+			return nil, fmt.Errorf("JSON inválido: %w", err)
+		}
+
+		return value, nil
+	default:
+		return text, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("valor '%s' não é do tipo %s", text, kind)
+	}
+
+	return value, nil
+}
+
+// decodeJSON mantém os números como json.Number, sem arredondar IDs acima de 2^53
+func decodeJSON(text string, value any) error {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(value); err != nil {
+		return err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("conteúdo após o fim do valor")
+	}
+
+	return nil
+}
+
+// clone copia objetos e arrays para que a linha não altere o caso de teste original
+func clone(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = clone(item)
+		}
+
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = clone(item)
+		}
+
+		return out
+	default:
+		return value
+	}
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,208 @@
+package dataset
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+)
+
+// applied é o resultado de uma linha aplicada ao caso de teste base
+type applied struct {
+	Line     int
+	RouteKey string
+	JSONPool map[string]any
+	Headers  map[string]any
+}
+
+func baseTestCase() rabbix.TestCase {
+	return rabbix.TestCase{
+		Name:     "pedido",
+		RouteKey: "pedidos",
+		JSONPool: map[string]any{"id": "0", "status": "novo"},
+	}
+}
+
+func applyRows(t *testing.T, rows []Row) []applied {
+	t.Helper()
+
+	var out []applied
+
+	for _, row := range rows {
+		tc, err := row.Apply(baseTestCase())
+		if err != nil {
+			t.Fatalf("Apply da linha %d: %v", row.Line, err)
+		}
+
+		out = append(out, applied{Line: row.Line, RouteKey: tc.RouteKey, JSONPool: tc.JSONPool, Headers: tc.Headers})
+	}
+
+	return out
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []applied
+		wantErr string
+	}{
+		{
+			name: "tipos das colunas",
+			data: "id:int,total:float,pago:bool,itens:json,nome\n7,9.5,true,\"[1,2]\",Ana\n",
+			want: []applied{{
+				Line: 2, RouteKey: "pedidos",
+				JSONPool: map[string]any{
+					"id": int64(7), "total": 9.5, "pago": true, "status": "novo",
+					"itens": []any{json.Number("1"), json.Number("2")}, "nome": "Ana",
+				},
+			}},
+		},
+		{
+			name: "headers, route key e caminhos aninhados",
+			data: "route_key,headers.tenant,json_pool.cliente.id:int\nfila-b,acme,3\n",
+			want: []applied{{
+				Line: 2, RouteKey: "fila-b",
+				JSONPool: map[string]any{"id": "0", "status": "novo", "cliente": map[string]any{"id": int64(3)}},
+				Headers:  map[string]any{"tenant": "acme"},
+			}},
+		},
+		{
+			name: "célula vazia mantém o valor do teste",
+			data: "id,status\n1,\n\n2,pago\n",
+			want: []applied{
+				{Line: 2, RouteKey: "pedidos", JSONPool: map[string]any{"id": "1", "status": "novo"}},
+				{Line: 4, RouteKey: "pedidos", JSONPool: map[string]any{"id": "2", "status": "pago"}},
+			},
+		},
+		{
+			name: "json preserva inteiros grandes",
+			data: "extra:json\n\"{\"\"id\"\":12345678901234567891}\"\n",
+			want: []applied{{
+				Line: 2, RouteKey: "pedidos",
+				JSONPool: map[string]any{
+					"id": "0", "status": "novo",
+					"extra": map[string]any{"id": json.Number("12345678901234567891")},
+				},
+			}},
+		},
+		{name: "vazio", data: "", wantErr: "arquivo CSV vazio"},
+		{name: "tipo inválido", data: "id:date\n1\n", wantErr: "tipo 'date' inválido"},
+		{name: "route key não texto", data: "route_key:int\n1\n", wantErr: "deve ser do tipo string"},
+		{name: "header sem nome", data: "headers.\nx\n", wantErr: "sem o nome do header"},
+		{name: "caminho inválido", data: "itens[x]\n1\n", wantErr: "caminho inválido"},
+		{name: "valor fora do tipo", data: "id:int\n1\nabc\n", wantErr: "linha 3, coluna 'id:int'"},
+		{name: "json com sobra", data: "x:json\n\"{} {}\"\n", wantErr: "JSON inválido"},
+		{name: "colunas a mais", data: "id\n1,2\n", wantErr: "CSV inválido"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseCSV([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseCSV erro = %v, esperado contendo %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseCSV erro inesperado: %v", err)
+			}
+
+			if got := applyRows(t, rows); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCSV = %#v, esperado %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []applied
+		wantErr string
+	}{
+		{
+			name: "valores json mantêm o tipo",
+			data: `{"id": 12345678901234567891, "pago": false, "cliente": {"nome": "Ana"}}` + "\n",
+			want: []applied{{
+				Line: 1, RouteKey: "pedidos",
+				JSONPool: map[string]any{
+					"id": json.Number("12345678901234567891"), "pago": false, "status": "novo",
+					"cliente": map[string]any{"nome": "Ana"},
+				},
+			}},
+		},
+		{
+			name: "texto convertido pelo tipo da chave",
+			data: "\n" + `{"qtd:int": "5", "headers.tenant": "acme", "route_key": "fila-b"}` + "\n\n",
+			want: []applied{{
+				Line: 2, RouteKey: "fila-b",
+				JSONPool: map[string]any{"id": "0", "status": "novo", "qtd": int64(5)},
+				Headers:  map[string]any{"tenant": "acme"},
+			}},
+		},
+		{
+			name: "caminho aninhado",
+			data: `{"itens[1].sku": "A1"}`,
+			want: []applied{{
+				Line: 1, RouteKey: "pedidos",
+				JSONPool: map[string]any{"id": "0", "status": "novo", "itens": []any{nil, map[string]any{"sku": "A1"}}},
+			}},
+		},
+		{name: "não objeto", data: `[1,2]`, wantErr: "linha 1: JSON inválido"},
+		{name: "conteúdo após o objeto", data: `{"id":1} {"id":2}`, wantErr: "conteúdo após o fim do valor"},
+		{name: "route key não texto", data: `{"route_key": 1}`, wantErr: "deve ser texto"},
+		{name: "valor fora do tipo", data: `{"id":1}` + "\n" + `{"id:int":"x"}`, wantErr: "linha 2, campo 'id:int'"},
+		{name: "tipo inválido", data: `{"id:uuid":"x"}`, wantErr: "tipo 'uuid' inválido"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseJSONLines([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseJSONLines erro = %v, esperado contendo %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseJSONLines erro inesperado: %v", err)
+			}
+
+			if got := applyRows(t, rows); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseJSONLines = %#v, esperado %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyKeepsTestCase(t *testing.T) {
+	rows, err := parseCSV([]byte("status,headers.tenant\npago,acme\n"))
+	if err != nil {
+		t.Fatalf("parseCSV erro inesperado: %v", err)
+	}
+
+	tc := baseTestCase()
+	if _, err := rows[0].Apply(tc); err != nil {
+		t.Fatalf("Apply erro inesperado: %v", err)
+	}
+
+	if tc.JSONPool["status"] != "novo" || tc.Headers != nil {
+		t.Errorf("Apply alterou o caso de teste original: %#v %#v", tc.JSONPool, tc.Headers)
+	}
+
+	tc.JSONPool = nil
+	tc.Payload = &rabbix.Payload{}
+
+	if _, err := rows[0].Apply(tc); err == nil || !strings.Contains(err.Error(), "usa 'payload'") {
+		t.Errorf("Apply em teste com payload: erro = %v", err)
+	}
+}
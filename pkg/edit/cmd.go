@@ -0,0 +1,228 @@
+package edit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/maxwelbm/rabbix/pkg/cache"
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	"github.com/maxwelbm/rabbix/pkg/sett"
+	"github.com/spf13/cobra"
+)
+
+// defaultEditor é usado quando $EDITOR e $VISUAL não estão definidos
+const defaultEditor = "vi"
+
+type Edit struct {
+	settings sett.SettItf
+	Cache    cache.CacheItf
+}
+
+func New(
+	settings sett.SettItf,
+	cache cache.CacheItf,
+) *Edit {
+	return &Edit{
+		settings: settings,
+		Cache:    cache,
+	}
+}
+
+func (e *Edit) CmdEdit() *cobra.Command {
+	var cmd = &cobra.Command{
+		Use:   "edit [test-name]",
+		Short: "Abre um caso de teste no editor ($EDITOR)",
+		Long: `Abre o JSON do caso de teste no editor definido em $EDITOR (ou $VISUAL, padrão: vi),
+sem precisar localizar o arquivo no output_dir da configuração ativa.
+Ao fechar o editor o conteúdo é validado como caso de teste; se estiver inválido o erro
+é exibido e o editor é reaberto com as alterações para correção. O arquivo só é gravado,
+e a data de atualização do cache só muda, quando o conteúdo realmente foi alterado.
+Exemplos:
+  rabbix edit meu-teste
+  EDITOR="code --wait" rabbix edit meu-teste`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			// Sincroniza cache antes de fornecer sugestões
+			e.Cache.SyncCacheWithFileSystem()
+
+			return e.Cache.GetCachedTests(), cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			testName := args[0]
+
+			settings := e.settings.LoadSettings()
+			outputDir := settings["output_dir"]
+			if outputDir == "" {
+				home, _ := os.UserHomeDir()
+				outputDir = filepath.Join(home, ".rabbix", "tests")
+			}
+
+			testPath := filepath.Join(outputDir, testName+".json")
+			original, err := os.ReadFile(testPath)
+			if err != nil {
+				output.Println("💡 Use 'rabbix list' para ver os testes disponíveis")
+				return exitcode.New(exitcode.NotFound, "teste '%s' não encontrado em %s", testName, testPath)
+			}
+
+			// Edita uma cópia para que um conteúdo inválido nunca substitua o teste
+			draft, err := os.CreateTemp("", "rabbix-"+testName+"-*.json")
+			if err != nil {
+				return fmt.Errorf("erro ao criar arquivo temporário: %w", err)
+			}
+
+			defer func() {
+				_ = os.Remove(draft.Name())
+			}()
+
+			_, err = draft.Write(original)
+			if closeErr := draft.Close(); err == nil {
+				err = closeErr
+			}
+
+			if err != nil {
+				return fmt.Errorf("erro ao criar arquivo temporário: %w", err)
+			}
+
+			input := bufio.NewReader(cmd.InOrStdin())
+
+			for {
+				if err := openEditor(draft.Name()); err != nil {
+					return err
+				}
+
+				edited, err := os.ReadFile(draft.Name())
+				if err != nil {
+					return fmt.Errorf("erro ao ler o arquivo editado: %w", err)
+				}
+
+				if bytes.Equal(edited, original) {
+					output.Printf("ℹ️  Nenhuma alteração em '%s'\n", testName)
+					return nil
+				}
+
+				if err := validate(edited); err != nil {
+					output.Printf("❌ Caso de teste inválido: %v\n", err)
+
+					if !confirmReopen(input) {
+						return exitcode.New(exitcode.Config, "alterações em '%s' descartadas", testName)
+					}
+
+					continue
+				}
+
+				// Mudanças apenas de formatação não contam como alteração
+				if sameContent(original, edited) {
+					output.Printf("ℹ️  Nenhuma alteração no conteúdo de '%s'\n", testName)
+					return nil
+				}
+
+				if err := os.WriteFile(testPath, edited, 0644); err != nil {
+					return fmt.Errorf("erro ao salvar teste: %w", err)
+				}
+
+				// Atualiza o cache com a nova route key e a data de atualização
+				e.Cache.SyncCacheWithFileSystem()
+
+				output.Printf("✅ Teste '%s' atualizado em %s\n", testName, testPath)
+
+				return nil
+			}
+		},
+	}
+
+	return cmd
+}
+
+// openEditor executa o editor conectado ao terminal. $EDITOR pode conter
+// argumentos, como "code --wait"
+func openEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if strings.TrimSpace(editor) == "" {
+		editor = os.Getenv("VISUAL")
+	}
+
+	if strings.TrimSpace(editor) == "" {
+		editor = defaultEditor
+	}
+
+	fields := strings.Fields(editor)
+
+	process := exec.Command(fields[0], append(fields[1:], path)...)
+	process.Stdin = os.Stdin
+	process.Stdout = os.Stdout
+	process.Stderr = os.Stderr
+
+	if err := process.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("o editor '%s' terminou com erro: %w", editor, err)
+		}
+
+		return exitcode.New(exitcode.Config, "erro ao abrir o editor '%s' (defina $EDITOR): %w", editor, err)
+	}
+
+	return nil
+}
+
+// validate exige um JSON com apenas os campos do caso de teste e os campos obrigatórios
+func validate(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var tc rabbix.TestCase
+	if err := decoder.Decode(&tc); err != nil {
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return fmt.Errorf("campo desconhecido %s", field)
+		}
+
+		return fmt.Errorf("JSON inválido: %w", err)
+	}
+
+	if decoder.More() {
+		return errors.New("JSON inválido: conteúdo após o fim do objeto")
+	}
+
+	return tc.Validate()
+}
+
+// sameContent compara os dois JSONs ignorando formatação e ordem das chaves
+func sameContent(a, b []byte) bool {
+	var left, right any
+
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(left, right)
+}
+
+// confirmReopen pergunta se o editor deve ser reaberto; Enter confirma
+func confirmReopen(input *bufio.Reader) bool {
+	output.Printf("↩️  Pressione Enter para corrigir no editor ou digite 'n' para descartar as alterações: ")
+
+	answer, err := input.ReadString('\n')
+	if err != nil && answer == "" {
+		output.Println()
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "" || answer == "s" || answer == "sim" || answer == "y"
+}
@@ -0,0 +1,71 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Códigos de saída do rabbix, estáveis para uso em scripts de CI
+const (
+	// Success indica que tudo foi executado com sucesso
+	Success = 0
+	// Failure é o código para erros sem classificação, como argumentos inválidos
+	Failure = 1
+	// Config indica configuração, flags ou caso de teste inválidos
+	Config = 2
+	// NotFound indica que o caso de teste não existe
+	NotFound = 3
+	// Publish indica que nenhuma publicação teve sucesso
+	Publish = 4
+	// Partial indica que parte das publicações do lote falhou
+	Partial = 5
+	// Assertion indica que as mensagens foram publicadas, mas o expect falhou
+	Assertion = 6
+	// Timeout indica que o tempo total da execução se esgotou antes do fim
+	Timeout = 7
+	// Interrupted indica que a execução foi interrompida com Ctrl+C, seguindo a
+	// convenção dos shells (128 + SIGINT)
+	Interrupted = 130
+)
+
+// Error associa um código de saída a um erro
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New cria um erro formatado com o código de saída informado
+func New(code int, format string, args ...any) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// Wrap associa o código de saída a um erro existente
+func Wrap(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Code: code, Err: err}
+}
+
+// Code retorna o código de saída do erro; erros sem código usam Failure
+func Code(err error) int {
+	if err == nil {
+		return Success
+	}
+
+	var exitErr *Error
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	return Failure
+}
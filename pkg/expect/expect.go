@@ -0,0 +1,387 @@
+package expect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/jpath"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	"github.com/maxwelbm/rabbix/pkg/request"
+	"github.com/maxwelbm/rabbix/pkg/tmpl"
+)
+
+const (
+	// pollInterval é o intervalo entre leituras da fila observada
+	pollInterval = 200 * time.Millisecond
+	// batchSize é a quantidade máxima de mensagens lidas por consulta
+	batchSize = 50
+)
+
+// Assertion é o resultado de uma verificação do bloco expect
+type Assertion struct {
+	Name     string `json:"name"`
+	Passed   bool   `json:"passed"`
+	Expected any    `json:"expected,omitempty"`
+	Actual   any    `json:"actual,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Result reúne as mensagens recebidas na fila observada e as asserções avaliadas
+type Result struct {
+	Queue      string            `json:"queue"`
+	Messages   []request.Message `json:"-"`
+	Assertions []Assertion       `json:"assertions"`
+}
+
+// Passed indica se todas as asserções passaram
+func (r Result) Passed() bool {
+	return passed(r.Assertions)
+}
+
+// Failed conta as asserções que falharam
+func (r Result) Failed() int {
+	failed := 0
+
+	for _, assertion := range r.Assertions {
+		if !assertion.Passed {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+func (a Assertion) String() string {
+	if a.Passed {
+		return "✅ " + a.Detail()
+	}
+
+	return "❌ " + a.Detail()
+}
+
+// Detail descreve a asserção sem emojis, para relatórios e tabelas
+func (a Assertion) Detail() string {
+	switch {
+	case a.Passed:
+		return a.Name
+	case a.Message != "":
+		return fmt.Sprintf("%s: %s", a.Name, a.Message)
+	default:
+		return fmt.Sprintf("%s: esperado %s, recebido %s", a.Name, format(a.Expected), format(a.Actual))
+	}
+}
+
+// Prepare esvazia a fila observada antes da publicação quando o expect pede
+// purge, para que mensagens que sobraram de execuções anteriores não sejam
+// avaliadas como efeito da nova publicação
+func Prepare(ctx context.Context, req request.RequestItf, tc rabbix.TestCase) error {
+	if tc.Expect == nil || !tc.Expect.Purge {
+		return nil
+	}
+
+	return req.Purge(ctx, request.PurgeOptions{
+		Queue:     tc.Expect.Queue,
+		Vhost:     vhostOf(tc),
+		Transport: tc.Transport,
+	})
+}
+
+// Check aguarda as mensagens da fila observada pelo caso de teste e avalia as
+// asserções do bloco expect. As mensagens lidas são consumidas da fila. Com o
+// contexto cancelado a espera termina e as mensagens já recebidas são avaliadas
+func Check(ctx context.Context, req request.RequestItf, tc rabbix.TestCase) (Result, error) {
+	expect := tc.Expect
+	if expect == nil {
+		return Result{}, nil
+	}
+
+	timeout, err := expect.TimeoutDuration()
+	if err != nil {
+		return Result{}, err
+	}
+
+	options := request.GetOptions{
+		Queue:     expect.Queue,
+		Vhost:     vhostOf(tc),
+		Transport: tc.Transport,
+		Count:     batchSize,
+		AckMode:   request.AckModeAck,
+	}
+
+	var messages []request.Message
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		received, err := req.Get(ctx, options)
+		if err != nil {
+			return Result{}, fmt.Errorf("erro ao ler a fila '%s': %w", expect.Queue, err)
+		}
+
+		messages = append(messages, received...)
+
+		if done(*expect, messages) || time.Now().After(deadline) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return Evaluate(*expect, messages), nil
+		case <-time.After(pollInterval):
+		}
+	}
+
+	return Evaluate(*expect, messages), nil
+}
+
+// Render avalia os placeholders dos valores esperados, ex: {"$.order.id": "{{vars.orderId}}"},
+// sem avançar o {{seq}}. Deve ser chamado depois da captura da mensagem publicada
+func Render(e rabbix.Expect, engine *tmpl.Engine) (rabbix.Expect, error) {
+	payload, err := engine.Render(e.Payload)
+	if err != nil {
+		return e, fmt.Errorf("expect.payload: %w", err)
+	}
+
+	headers, err := engine.Render(e.Headers)
+	if err != nil {
+		return e, fmt.Errorf("expect.headers: %w", err)
+	}
+
+	equals, err := engine.Render(e.Equals)
+	if err != nil {
+		return e, fmt.Errorf("expect.equals: %w", err)
+	}
+
+	e.Payload, _ = payload.(map[string]any)
+	e.Headers, _ = headers.(map[string]any)
+	e.Equals = equals
+
+	return e, nil
+}
+
+// Evaluate avalia as asserções do bloco expect sobre as mensagens recebidas.
+// As asserções de conteúdo usam a primeira mensagem que satisfaz todas elas
+// ou, se nenhuma satisfizer, a primeira mensagem recebida
+func Evaluate(expect rabbix.Expect, messages []request.Message) Result {
+	result := Result{
+		Queue:    expect.Queue,
+		Messages: messages,
+	}
+
+	if expect.Count != nil {
+		assertion := Assertion{
+			Name:     "quantidade de mensagens",
+			Passed:   len(messages) == *expect.Count,
+			Expected: *expect.Count,
+			Actual:   len(messages),
+		}
+		result.Assertions = append(result.Assertions, assertion)
+	} else {
+		assertion := Assertion{
+			Name:   "mensagem recebida",
+			Passed: len(messages) > 0,
+		}
+
+		if !assertion.Passed {
+			assertion.Message = fmt.Sprintf("nenhuma mensagem na fila '%s'", expect.Queue)
+		}
+
+		result.Assertions = append(result.Assertions, assertion)
+	}
+
+	if !hasContentAssertions(expect) || (expect.Count != nil && *expect.Count == 0) {
+		return result
+	}
+
+	if len(messages) == 0 {
+		for _, name := range contentNames(expect) {
+			result.Assertions = append(result.Assertions, Assertion{
+				Name:    name,
+				Message: "nenhuma mensagem para avaliar",
+			})
+		}
+
+		return result
+	}
+
+	candidate := assertMessage(expect, messages[0])
+
+	for _, msg := range messages {
+		if assertions := assertMessage(expect, msg); passed(assertions) {
+			candidate = assertions
+			break
+		}
+	}
+
+	result.Assertions = append(result.Assertions, candidate...)
+
+	return result
+}
+
+// vhostOf retorna o vhost da fila observada, por padrão o da publicação
+func vhostOf(tc rabbix.TestCase) string {
+	if tc.Expect.Vhost != "" {
+		return tc.Expect.Vhost
+	}
+
+	return tc.Vhost
+}
+
+// done indica se já é possível encerrar a espera
+func done(expect rabbix.Expect, messages []request.Message) bool {
+	if expect.Count != nil {
+		// count zero exige aguardar o timeout inteiro
+		return *expect.Count > 0 && len(messages) >= *expect.Count
+	}
+
+	for _, msg := range messages {
+		if passed(assertMessage(expect, msg)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// assertMessage avalia as asserções de payload e headers sobre uma mensagem
+func assertMessage(expect rabbix.Expect, msg request.Message) []Assertion {
+	var assertions []Assertion
+
+	var (
+		payload    any
+		payloadErr error
+	)
+
+	if body, err := msg.Body(); err != nil {
+		payloadErr = err
+	} else if err := json.Unmarshal(body, &payload); err != nil {
+		payloadErr = fmt.Errorf("payload não é JSON: %w", err)
+	}
+
+	for _, path := range sortedKeys(expect.Payload) {
+		name := "payload " + path
+		if payloadErr != nil {
+			assertions = append(assertions, Assertion{Name: name, Message: payloadErr.Error()})
+			continue
+		}
+
+		assertions = append(assertions, assertPath(name, payload, path, expect.Payload[path]))
+	}
+
+	headers := map[string]any{}
+	for key, value := range msg.Headers() {
+		headers[key] = value
+	}
+
+	for _, path := range sortedKeys(expect.Headers) {
+		assertions = append(assertions, assertPath("header "+path, headers, path, expect.Headers[path]))
+	}
+
+	if expect.Equals != nil {
+		assertion := Assertion{Name: "payload igual", Expected: expect.Equals}
+		if payloadErr != nil {
+			assertion.Message = payloadErr.Error()
+		} else {
+			assertion.Actual = payload
+			assertion.Passed = equal(expect.Equals, payload)
+		}
+
+		assertions = append(assertions, assertion)
+	}
+
+	return assertions
+}
+
+func assertPath(name string, root any, path string, expected any) Assertion {
+	assertion := Assertion{Name: name, Expected: expected}
+
+	actual, found, err := jpath.Get(root, path)
+
+	switch {
+	case err != nil:
+		assertion.Message = err.Error()
+	case !found:
+		assertion.Message = fmt.Sprintf("caminho não encontrado (esperado %s)", format(expected))
+	default:
+		assertion.Actual = actual
+		assertion.Passed = equal(expected, actual)
+	}
+
+	return assertion
+}
+
+// equal compara dois valores pela representação JSON, para que 2 e 2.0 ou
+// números vindos de transportes diferentes sejam considerados iguais
+func equal(expected, actual any) bool {
+	return reflect.DeepEqual(normalize(expected), normalize(actual))
+}
+
+func normalize(value any) any {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var normalized any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+
+	return normalized
+}
+
+func format(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(data)
+}
+
+func passed(assertions []Assertion) bool {
+	for _, assertion := range assertions {
+		if !assertion.Passed {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasContentAssertions(expect rabbix.Expect) bool {
+	return len(expect.Payload) > 0 || len(expect.Headers) > 0 || expect.Equals != nil
+}
+
+func contentNames(expect rabbix.Expect) []string {
+	var names []string
+
+	for _, path := range sortedKeys(expect.Payload) {
+		names = append(names, "payload "+path)
+	}
+
+	for _, path := range sortedKeys(expect.Headers) {
+		names = append(names, "header "+path)
+	}
+
+	if expect.Equals != nil {
+		names = append(names, "payload igual")
+	}
+
+	return names
+}
+
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
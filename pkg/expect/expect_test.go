@@ -0,0 +1,179 @@
+package expect
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	"github.com/maxwelbm/rabbix/pkg/request"
+)
+
+// fakeQueue simula a fila observada: cada Get entrega o próximo lote e Purge
+// descarta o que ainda não foi lido
+type fakeQueue struct {
+	request.RequestItf
+
+	batches [][]request.Message
+	getErr  error
+	purges  []request.PurgeOptions
+	gets    []request.GetOptions
+}
+
+func (f *fakeQueue) Purge(_ context.Context, options request.PurgeOptions) error {
+	f.purges = append(f.purges, options)
+	f.batches = nil
+
+	return nil
+}
+
+func (f *fakeQueue) Get(_ context.Context, options request.GetOptions) ([]request.Message, error) {
+	f.gets = append(f.gets, options)
+
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+
+	if len(f.batches) == 0 {
+		return nil, nil
+	}
+
+	batch := f.batches[0]
+	f.batches = f.batches[1:]
+
+	return batch, nil
+}
+
+func message(payload string) request.Message {
+	return request.Message{Payload: payload, PayloadEncoding: "string"}
+}
+
+func TestPrepareOnlyPurgesWhenRequested(t *testing.T) {
+	queue := &fakeQueue{}
+	tc := rabbix.TestCase{Vhost: "/loja", Transport: "amqp", Expect: &rabbix.Expect{Queue: "pedidos.criados"}}
+
+	if err := Prepare(context.Background(), queue, tc); err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+
+	if len(queue.purges) != 0 {
+		t.Fatalf("Prepare esvaziou a fila sem expect.purge: %+v", queue.purges)
+	}
+
+	tc.Expect.Purge = true
+	if err := Prepare(context.Background(), queue, tc); err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+
+	want := request.PurgeOptions{Queue: "pedidos.criados", Vhost: "/loja", Transport: "amqp"}
+	if len(queue.purges) != 1 || queue.purges[0] != want {
+		t.Errorf("purges = %+v, esperado %+v", queue.purges, want)
+	}
+
+	if err := Prepare(context.Background(), queue, rabbix.TestCase{}); err != nil || len(queue.purges) != 1 {
+		t.Errorf("Prepare sem expect: erro %v, purges %d", err, len(queue.purges))
+	}
+}
+
+func TestCheckWaitsForMatchingMessage(t *testing.T) {
+	// A mensagem antiga chega primeiro; a esperada só no segundo lote
+	queue := &fakeQueue{batches: [][]request.Message{
+		{message(`{"order":{"id":1}}`)},
+		{message(`{"order":{"id":2}}`)},
+	}}
+	tc := rabbix.TestCase{Expect: &rabbix.Expect{
+		Queue:   "pedidos.criados",
+		Vhost:   "/eventos",
+		Timeout: "2s",
+		Payload: map[string]any{"$.order.id": 2},
+	}}
+
+	result, err := Check(context.Background(), queue, tc)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	if !result.Passed() || len(result.Messages) != 2 {
+		t.Fatalf("Check = %+v, esperado aprovado com 2 mensagens", result)
+	}
+
+	if len(queue.gets) != 2 {
+		t.Errorf("Check leu a fila %d vez(es), esperado parar ao encontrar a mensagem", len(queue.gets))
+	}
+
+	// As mensagens lidas são consumidas, no vhost do expect
+	if options := queue.gets[0]; options.AckMode != request.AckModeAck || options.Vhost != "/eventos" {
+		t.Errorf("GetOptions = %+v, esperado ack no vhost /eventos", options)
+	}
+}
+
+func TestCheckStaleMessageWithoutPurge(t *testing.T) {
+	// Sem purge, uma mensagem antiga com o mesmo formato é avaliada como se fosse nova
+	queue := &fakeQueue{batches: [][]request.Message{{message(`{"status":"criado"}`)}}}
+	tc := rabbix.TestCase{Expect: &rabbix.Expect{
+		Queue:   "pedidos.criados",
+		Timeout: "1s",
+		Payload: map[string]any{"$.status": "criado"},
+	}}
+
+	result, err := Check(context.Background(), queue, tc)
+	if err != nil || !result.Passed() {
+		t.Fatalf("Check sem purge = %+v, %v; esperado aprovado pela mensagem antiga", result, err)
+	}
+
+	// Com purge a mensagem antiga é descartada antes da publicação e o expect falha
+	queue = &fakeQueue{batches: [][]request.Message{{message(`{"status":"criado"}`)}}}
+	tc.Expect.Purge = true
+	tc.Expect.Timeout = "300ms"
+
+	if err := Prepare(context.Background(), queue, tc); err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+
+	result, err = Check(context.Background(), queue, tc)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	if result.Passed() || result.Failed() != 2 {
+		t.Errorf("Check após purge = %+v, esperado falha de recebimento e de payload", result.Assertions)
+	}
+}
+
+func TestCheckCountWaitsForTimeout(t *testing.T) {
+	zero := 0
+	queue := &fakeQueue{}
+	tc := rabbix.TestCase{Expect: &rabbix.Expect{Queue: "dlq", Timeout: "300ms", Count: &zero}}
+
+	result, err := Check(context.Background(), queue, tc)
+	if err != nil || !result.Passed() {
+		t.Fatalf("Check count 0 = %+v, %v", result, err)
+	}
+
+	if len(queue.gets) < 2 {
+		t.Errorf("count 0 leu a fila %d vez(es), esperado aguardar o timeout", len(queue.gets))
+	}
+}
+
+func TestCheckReadError(t *testing.T) {
+	queue := &fakeQueue{getErr: errors.New("status 404")}
+	tc := rabbix.TestCase{Expect: &rabbix.Expect{Queue: "inexistente"}}
+
+	if _, err := Check(context.Background(), queue, tc); err == nil {
+		t.Fatal("Check deveria falhar quando a fila não pode ser lida")
+	}
+}
+
+func TestCheckCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	queue := &fakeQueue{}
+	tc := rabbix.TestCase{Expect: &rabbix.Expect{Queue: "pedidos.criados", Timeout: "1m"}}
+
+	result, err := Check(ctx, queue, tc)
+	if err != nil || result.Passed() || len(queue.gets) != 1 {
+		t.Errorf("Check cancelado = %+v, %v, %d leitura(s); esperado falha após uma leitura",
+			result.Assertions, err, len(queue.gets))
+	}
+}
@@ -0,0 +1,198 @@
+package get
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/request"
+	"github.com/spf13/cobra"
+)
+
+type Get struct {
+	request request.RequestItf
+}
+
+func New(request request.RequestItf) *Get {
+	return &Get{
+		request: request,
+	}
+}
+
+func (g *Get) CmdGet() *cobra.Command {
+	var (
+		options request.GetOptions
+		jsonl   string
+	)
+
+	var cmd = &cobra.Command{
+		Use:   "get [queue]",
+		Short: "Lê mensagens de uma fila para inspeção",
+		Long: `Lê mensagens de uma fila e exibe payload, headers e properties.
+Por padrão as mensagens são recolocadas na fila após a leitura.
+Exemplos:
+  rabbix get pedidos
+  rabbix get pedidos --count 10 --jsonl mensagens.jsonl
+  rabbix get pedidos --count 5 --requeue=false   # consome as mensagens
+  rabbix get pedidos --ack reject --requeue=false # envia para a dead letter`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.Queue = args[0]
+
+			// Com --jsonl - a saída padrão recebe apenas as linhas JSON
+			quiet := jsonl == "-"
+			if !quiet {
+				output.Printf("📥 Lendo até %d mensagem(ns) da fila '%s'\n", options.Count, options.Queue)
+				if !options.Requeue {
+					output.Println("⚠️  As mensagens lidas serão removidas da fila (--requeue=false)")
+				}
+
+				output.Println("─────────────────────────────────────")
+			}
+
+			defer func() {
+				if err := g.request.Close(); err != nil {
+					output.Printf("❌ Erro ao encerrar conexão: %v\n", err)
+				}
+			}()
+
+			messages, err := g.request.Get(cmd.Context(), options)
+			if err != nil {
+				if request.IsConfigError(err) {
+					return exitcode.Wrap(exitcode.Config, err)
+				}
+
+				return err
+			}
+
+			if jsonl != "" {
+				if err := writeJSONLines(cmd.OutOrStdout(), jsonl, messages); err != nil {
+					return fmt.Errorf("erro ao gravar mensagens: %w", err)
+				}
+			}
+
+			// A saída padrão já recebeu as linhas JSON
+			if quiet {
+				return nil
+			}
+
+			result := newResult(options.Queue, messages)
+
+			return output.Render(result, result.table(), func() {
+				if len(messages) == 0 {
+					output.Println("📭 Nenhuma mensagem na fila")
+					return
+				}
+
+				for i, msg := range messages {
+					printMessage(i+1, len(messages), msg)
+				}
+
+				output.Println("─────────────────────────────────────")
+				output.Printf("✅ %d mensagem(ns) lida(s) | Restantes na fila: %d\n", len(messages), result.Remaining)
+
+				if jsonl != "" {
+					output.Printf("💾 Mensagens gravadas em %s\n", jsonl)
+				}
+			})
+		},
+	}
+
+	cmd.Flags().IntVarP(&options.Count, "count", "n", 1,
+		"Quantidade máxima de mensagens lidas")
+	cmd.Flags().StringVar(&options.AckMode, "ack", request.AckModeAck,
+		"Modo de confirmação: ack ou reject (reject sem requeue envia para a dead letter)")
+	cmd.Flags().BoolVar(&options.Requeue, "requeue", true,
+		"Recoloca as mensagens na fila após a leitura")
+	cmd.Flags().StringVar(&options.Vhost, "vhost", "",
+		"Virtual host da fila (padrão: o da configuração ativa)")
+	cmd.Flags().StringVar(&options.Transport, "transport", "",
+		"Transporte usado na leitura: http (API de gerenciamento) ou amqp")
+	cmd.Flags().StringVar(&jsonl, "jsonl", "",
+		"Grava as mensagens em JSON Lines no arquivo informado ('-' para a saída padrão)")
+
+	return cmd
+}
+
+func printMessage(index, total int, msg request.Message) {
+	output.Printf("📨 [%d/%d] Exchange: %s | Routing key: %s | Redelivered: %t\n",
+		index, total, displayExchange(msg.Exchange), msg.RoutingKey, msg.Redelivered)
+
+	properties := map[string]any{}
+	for key, value := range msg.Properties {
+		if key != "headers" {
+			properties[key] = value
+		}
+	}
+
+	if len(properties) > 0 {
+		output.Printf("🏷️  Properties:\n%s\n", indentJSON(properties))
+	}
+
+	if headers := msg.Headers(); len(headers) > 0 {
+		output.Printf("📋 Headers:\n%s\n", indentJSON(headers))
+	}
+
+	if msg.PayloadEncoding == "base64" {
+		output.Printf("📄 Payload (base64, %d bytes):\n%s\n", msg.PayloadBytes, msg.Payload)
+		return
+	}
+
+	// Payloads JSON são indentados, os demais exibidos como estão
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, []byte(msg.Payload), "", "  "); err == nil {
+		output.Printf("📄 Payload:\n%s\n", pretty.String())
+	} else {
+		output.Printf("📄 Payload:\n%s\n", msg.Payload)
+	}
+}
+
+func displayExchange(exchange string) string {
+	if exchange == "" {
+		return "(default)"
+	}
+
+	return exchange
+}
+
+func indentJSON(value any) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
+
+// writeJSONLines grava uma mensagem por linha no arquivo ou na saída padrão
+func writeJSONLines(stdout io.Writer, path string, messages []request.Message) error {
+	out := stdout
+
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = file.Close()
+		}()
+
+		out = file
+	}
+
+	encoder := json.NewEncoder(out)
+	for _, msg := range messages {
+		if err := encoder.Encode(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package get
+
+import (
+	"strconv"
+
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/request"
+)
+
+// payloadWidth limita o payload exibido na tabela
+const payloadWidth = 60
+
+// Result é a leitura da fila na saída estruturada
+type Result struct {
+	Queue     string            `json:"queue"`
+	Count     int               `json:"count"`
+	Remaining int               `json:"remaining"`
+	Messages  []request.Message `json:"messages"`
+}
+
+func newResult(queue string, messages []request.Message) Result {
+	result := Result{
+		Queue:    queue,
+		Count:    len(messages),
+		Messages: messages,
+	}
+
+	if result.Messages == nil {
+		result.Messages = []request.Message{}
+	}
+
+	if len(messages) > 0 {
+		result.Remaining = messages[len(messages)-1].MessageCount
+	}
+
+	return result
+}
+
+func (r Result) table() *output.Table {
+	table := &output.Table{Header: []string{"#", "EXCHANGE", "ROUTING KEY", "REDELIVERED", "BYTES", "PAYLOAD"}}
+
+	for i, msg := range r.Messages {
+		payload := []rune(msg.Payload)
+		if len(payload) > payloadWidth {
+			payload = append(payload[:payloadWidth-1], '…')
+		}
+
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(i + 1),
+			displayExchange(msg.Exchange),
+			msg.RoutingKey,
+			strconv.FormatBool(msg.Redelivered),
+			strconv.Itoa(msg.PayloadBytes),
+			string(payload),
+		})
+	}
+
+	return table
+}
@@ -1,78 +1,117 @@
 package health
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
-	"strings"
+	"strconv"
 
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/request"
 	"github.com/maxwelbm/rabbix/pkg/sett"
 	"github.com/spf13/cobra"
 )
 
+// Result é o resultado do health na saída estruturada
+type Result struct {
+	URL        string          `json:"url"`
+	Status     string          `json:"status"`
+	StatusCode int             `json:"status_code"`
+	Healthy    bool            `json:"healthy"`
+	Overview   json.RawMessage `json:"overview,omitempty"`
+	Response   string          `json:"response,omitempty"`
+	Error      string          `json:"error,omitempty"`
+}
+
 func CmdHealth(settings sett.SettItf) *cobra.Command {
 	return &cobra.Command{
 		Use:   "health",
 		Short: "Verifica o status de saúde da API do RabbitMQ",
 		Long: `Faz uma requisição para o endpoint /api/overview para verificar se a API do "+
 "RabbitMQ está funcionando corretamente.`,
-		Run: func(cmd *cobra.Command, args []string) {
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			settings := settings.LoadSettings()
 
-			var auth = settings["auth"]
-			if auth == "" {
-				fmt.Printf("necessario configurar user e password com o comando " +
-					"'rabbix conf set --user <user> --password <password>'\n")
-				return
-			}
-
-			auth = "Basic " + auth
-
-			var host = "http://localhost:15672" // host default
-			if settings["host"] != "" {
-				host = settings["host"]
-			}
-
-			url := strings.TrimRight(host, "/") + "/api/overview"
+			var result Result
 
-			fmt.Printf("🔍 Verificando saúde da API...\n")
-			fmt.Printf("📡 URL: %s\n", url)
-
-			req, err := http.NewRequest("GET", url, nil)
+			req, err := request.NewManagementRequest(cmd.Context(), settings, "GET", "/api/overview", nil)
 			if err != nil {
-				fmt.Printf("❌ Erro ao criar requisição: %v\n", err)
-				return
+				return unhealthy(result, exitcode.New(exitcode.Config, "erro ao criar requisição: %w", err))
 			}
 
-			req.Header.Add("Authorization", auth)
+			result.URL = req.URL.String()
+
+			output.Printf("🔍 Verificando saúde da API...\n")
+			output.Printf("📡 URL: %s\n", req.URL)
 
-			client := &http.Client{}
-			resp, err := client.Do(req)
+			resp, err := request.ManagementClient().Do(req)
 			if err != nil {
-				fmt.Printf("❌ Erro ao fazer requisição: %v\n", err)
-				return
+				return unhealthy(result, fmt.Errorf("erro ao fazer requisição: %w", err))
 			}
 			defer func() {
 				if err := resp.Body.Close(); err != nil {
-					fmt.Printf("❌ Erro ao fechar corpo da resposta: %v\n", err)
+					output.Printf("❌ Erro ao fechar corpo da resposta: %v\n", err)
 				}
 			}()
 
+			result.Status = resp.Status
+			result.StatusCode = resp.StatusCode
+
 			body, err := io.ReadAll(resp.Body)
 			if err != nil {
-				fmt.Printf("❌ Erro ao ler resposta: %v\n", err)
-				return
+				return unhealthy(result, fmt.Errorf("erro ao ler resposta: %w", err))
 			}
 
-			fmt.Printf("📊 Status: %s\n", resp.Status)
+			result.Healthy = resp.StatusCode >= 200 && resp.StatusCode < 300
+			result.Response = string(body)
 
-			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-				fmt.Printf("✅ API está funcionando corretamente!\n")
-			} else {
-				fmt.Printf("⚠️  API retornou status de erro\n")
+			// Mantém o JSON do overview estruturado na saída json/yaml
+			if json.Valid(body) {
+				result.Overview = json.RawMessage(body)
+				result.Response = ""
 			}
 
-			fmt.Printf("📄 Resposta:\n%s\n", string(body))
+			if !result.Healthy {
+				result.Error = fmt.Sprintf("API retornou status de erro: %s", resp.Status)
+			}
+
+			err = output.Render(result, result.table(), func() {
+				output.Printf("📊 Status: %s\n", resp.Status)
+
+				if result.Healthy {
+					output.Printf("✅ API está funcionando corretamente!\n")
+				}
+
+				output.Printf("📄 Resposta:\n%s\n", string(body))
+			})
+			if err != nil || result.Healthy {
+				return err
+			}
+
+			return errors.New(result.Error)
 		},
 	}
 }
+
+// unhealthy emite o resultado com a falha na saída estruturada e devolve o erro,
+// que na saída de texto é exibido pelo comando raiz
+func unhealthy(result Result, err error) error {
+	result.Error = err.Error()
+
+	if renderErr := output.Render(result, result.table(), nil); renderErr != nil {
+		return renderErr
+	}
+
+	return err
+}
+
+func (r Result) table() *output.Table {
+	return &output.Table{
+		Header: []string{"URL", "STATUS", "SAUDÁVEL"},
+		Rows:   [][]string{{r.URL, r.Status, strconv.FormatBool(r.Healthy)}},
+	}
+}
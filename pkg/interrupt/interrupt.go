@@ -0,0 +1,109 @@
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/output"
+)
+
+// ErrInterrupted indica que a execução foi interrompida com Ctrl+C
+var ErrInterrupted = errors.New("execução interrompida com Ctrl+C")
+
+// ErrTimeout indica que o tempo total da execução se esgotou
+var ErrTimeout = errors.New("tempo total de execução esgotado")
+
+// Scope separa os dois níveis de cancelamento de uma execução: o Ctrl+C apenas
+// impede novas publicações, deixando as que estão em andamento terminarem, enquanto
+// o tempo total cancela também as publicações em andamento
+type Scope struct {
+	// Work é o contexto das publicações, cancelado apenas pelo tempo total
+	Work context.Context
+	// Schedule é cancelado também pelo Ctrl+C; nenhuma publicação nova começa depois dele
+	Schedule context.Context
+
+	cancelWork     context.CancelFunc
+	cancelSchedule context.CancelCauseFunc
+	signals        chan os.Signal
+}
+
+// New cria o escopo da execução. Um total zero não limita o tempo. Após o
+// primeiro Ctrl+C o comportamento padrão do sinal é restaurado, então um
+// segundo Ctrl+C encerra o processo imediatamente
+func New(parent context.Context, total time.Duration) *Scope {
+	scope := &Scope{signals: make(chan os.Signal, 1)}
+
+	if total > 0 {
+		scope.Work, scope.cancelWork = context.WithTimeoutCause(parent, total, ErrTimeout)
+	} else {
+		scope.Work, scope.cancelWork = context.WithCancel(parent)
+	}
+
+	scope.Schedule, scope.cancelSchedule = context.WithCancelCause(scope.Work)
+
+	signal.Notify(scope.signals, os.Interrupt)
+
+	go func() {
+		select {
+		case <-scope.signals:
+			signal.Stop(scope.signals)
+			output.Println("\n⚠️  Interrupção recebida: aguardando as publicações em andamento " +
+				"(Ctrl+C novamente para encerrar imediatamente)")
+			scope.cancelSchedule(ErrInterrupted)
+		case <-scope.Schedule.Done():
+		}
+	}()
+
+	return scope
+}
+
+// Stopped indica se novas publicações não devem mais ser iniciadas
+func (s *Scope) Stopped() bool {
+	return s.Schedule.Err() != nil
+}
+
+// Sleep espera pelo intervalo informado ou até o agendamento ser interrompido.
+// Retorna false quando interrompido
+func (s *Scope) Sleep(d time.Duration) bool {
+	if d <= 0 {
+		return !s.Stopped()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-s.Schedule.Done():
+		return false
+	}
+}
+
+// Err retorna o motivo da interrupção com o código de saída correspondente,
+// ou nil quando a execução não foi interrompida
+func (s *Scope) Err() error {
+	cause := context.Cause(s.Schedule)
+
+	switch {
+	case errors.Is(cause, ErrInterrupted):
+		return exitcode.Wrap(exitcode.Interrupted, cause)
+	case errors.Is(cause, ErrTimeout):
+		return exitcode.Wrap(exitcode.Timeout, cause)
+	case cause != nil:
+		return exitcode.Wrap(exitcode.Failure, cause)
+	default:
+		return nil
+	}
+}
+
+// Close libera o sinal e os contextos; deve ser chamado ao fim do comando
+func (s *Scope) Close() {
+	signal.Stop(s.signals)
+	s.cancelSchedule(nil)
+	s.cancelWork()
+}
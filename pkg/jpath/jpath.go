@@ -0,0 +1,182 @@
+package jpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxIndex limita os índices gravados por Set, já que os arrays crescem até o
+// índice pedido: "items[999999999]" alocaria um bilhão de posições
+const MaxIndex = 10000
+
+// Segment é um trecho de um caminho: uma chave de objeto ou um índice de array
+type Segment struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+func (s Segment) String() string {
+	if s.IsIndex {
+		return "[" + strconv.Itoa(s.Index) + "]"
+	}
+
+	return s.Key
+}
+
+// Parse interpreta caminhos como "order.items[0].sku". O prefixo "$" do
+// JSONPath é opcional, então "$.order.id" e "order.id" são equivalentes
+func Parse(path string) ([]Segment, error) {
+	rest := strings.TrimSpace(path)
+	rest = strings.TrimPrefix(rest, "$")
+	rest = strings.TrimPrefix(rest, ".")
+
+	if rest == "" {
+		return nil, nil
+	}
+
+	var segments []Segment
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if rest == "" || rest[0] == '.' || rest[0] == '[' {
+				return nil, fmt.Errorf("caminho inválido '%s': chave vazia", path)
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("caminho inválido '%s': ']' não encontrado", path)
+			}
+
+			inner := rest[1:end]
+
+			// Aceita chaves entre aspas, ex: headers["x-tenant"]
+			if unquoted, err := strconv.Unquote(inner); err == nil {
+				segments = append(segments, Segment{Key: unquoted})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("caminho inválido '%s': índice '%s'", path, inner)
+				}
+
+				segments = append(segments, Segment{Index: index, IsIndex: true})
+			}
+
+			rest = rest[end+1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			segments = append(segments, Segment{Key: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+
+	return segments, nil
+}
+
+// Get retorna o valor no caminho informado e se ele existe
+func Get(root any, path string) (any, bool, error) {
+	segments, err := Parse(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	current := root
+	for _, segment := range segments {
+		if segment.IsIndex {
+			items, ok := current.([]any)
+			if !ok || segment.Index >= len(items) {
+				return nil, false, nil
+			}
+
+			current = items[segment.Index]
+
+			continue
+		}
+
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false, nil
+		}
+
+		if current, ok = object[segment.Key]; !ok {
+			return nil, false, nil
+		}
+	}
+
+	return current, true, nil
+}
+
+// Set grava o valor no caminho, criando objetos e arrays intermediários
+func Set(root map[string]any, path string, value any) error {
+	segments, err := Parse(path)
+	if err != nil {
+		return err
+	}
+
+	if len(segments) == 0 || segments[0].IsIndex {
+		return fmt.Errorf("caminho inválido '%s': deve começar por uma chave", path)
+	}
+
+	_, err = set(root, segments, value, path)
+
+	return err
+}
+
+// set grava recursivamente e devolve o contêiner atualizado, já que arrays
+// podem precisar crescer para comportar o índice
+func set(current any, segments []Segment, value any, path string) (any, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	segment := segments[0]
+
+	if segment.IsIndex {
+		items, ok := current.([]any)
+		if current != nil && !ok {
+			return nil, fmt.Errorf("caminho '%s': esperado array antes de %s", path, segment)
+		}
+
+		if segment.Index > MaxIndex {
+			return nil, fmt.Errorf("caminho '%s': índice %s acima do limite de %d", path, segment, MaxIndex)
+		}
+
+		for len(items) <= segment.Index {
+			items = append(items, nil)
+		}
+
+		child, err := set(items[segment.Index], segments[1:], value, path)
+		if err != nil {
+			return nil, err
+		}
+
+		items[segment.Index] = child
+
+		return items, nil
+	}
+
+	object, ok := current.(map[string]any)
+	if current != nil && !ok {
+		return nil, fmt.Errorf("caminho '%s': esperado objeto antes de '%s'", path, segment)
+	}
+
+	if object == nil {
+		object = map[string]any{}
+	}
+
+	child, err := set(object[segment.Key], segments[1:], value, path)
+	if err != nil {
+		return nil, err
+	}
+
+	object[segment.Key] = child
+
+	return object, nil
+}
@@ -0,0 +1,149 @@
+package jpath
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    []Segment
+		wantErr bool
+	}{
+		{name: "vazio", path: "", want: nil},
+		{name: "apenas raiz", path: "$", want: nil},
+		{name: "chave simples", path: "id", want: []Segment{{Key: "id"}}},
+		{
+			name: "prefixo jsonpath",
+			path: "$.order.id",
+			want: []Segment{{Key: "order"}, {Key: "id"}},
+		},
+		{
+			name: "índice",
+			path: "order.items[2].sku",
+			want: []Segment{{Key: "order"}, {Key: "items"}, {Index: 2, IsIndex: true}, {Key: "sku"}},
+		},
+		{
+			name: "chave entre aspas",
+			path: `headers["x-tenant"]`,
+			want: []Segment{{Key: "headers"}, {Key: "x-tenant"}},
+		},
+		{
+			name: "índices encadeados",
+			path: "matrix[0][1]",
+			want: []Segment{{Key: "matrix"}, {Index: 0, IsIndex: true}, {Index: 1, IsIndex: true}},
+		},
+		{name: "chave vazia", path: "order..id", wantErr: true},
+		{name: "ponto final", path: "order.", wantErr: true},
+		{name: "colchete sem fechar", path: "items[0", wantErr: true},
+		{name: "índice negativo", path: "items[-1]", wantErr: true},
+		{name: "índice não numérico", path: "items[a]", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) erro = %v, esperado erro: %v", tt.path, err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %#v, esperado %#v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	root := map[string]any{
+		"order": map[string]any{
+			"id":    float64(7),
+			"items": []any{map[string]any{"sku": "A1"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		want      any
+		wantFound bool
+	}{
+		{name: "chave aninhada", path: "order.id", want: float64(7), wantFound: true},
+		{name: "item de array", path: "$.order.items[0].sku", want: "A1", wantFound: true},
+		{name: "chave ausente", path: "order.total"},
+		{name: "índice fora do array", path: "order.items[3].sku"},
+		{name: "índice em objeto", path: "order[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found, err := Get(root, tt.path)
+			if err != nil {
+				t.Fatalf("Get(%q) erro inesperado: %v", tt.path, err)
+			}
+
+			if found != tt.wantFound || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get(%q) = %v, %v; esperado %v, %v", tt.path, got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		root    map[string]any
+		path    string
+		value   any
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name:  "cria objetos intermediários",
+			root:  map[string]any{},
+			path:  "order.customer.id",
+			value: 1,
+			want:  map[string]any{"order": map[string]any{"customer": map[string]any{"id": 1}}},
+		},
+		{
+			name:  "cresce array até o índice",
+			root:  map[string]any{},
+			path:  "items[2].sku",
+			value: "A1",
+			want:  map[string]any{"items": []any{nil, nil, map[string]any{"sku": "A1"}}},
+		},
+		{
+			name:  "sobrescreve valor existente",
+			root:  map[string]any{"id": 1, "name": "x"},
+			path:  "$.id",
+			value: 2,
+			want:  map[string]any{"id": 2, "name": "x"},
+		},
+		{
+			name:  "índice no limite",
+			root:  map[string]any{},
+			path:  "items[10000]",
+			value: true,
+		},
+		{name: "índice acima do limite", root: map[string]any{}, path: "items[10001]", wantErr: true},
+		{name: "começa por índice", root: map[string]any{}, path: "[0]", wantErr: true},
+		{name: "caminho vazio", root: map[string]any{}, path: "", wantErr: true},
+		{name: "índice em objeto", root: map[string]any{"order": map[string]any{}}, path: "order[0]", wantErr: true},
+		{name: "chave em array", root: map[string]any{"items": []any{}}, path: "items.sku", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Set(tt.root, tt.path, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Set(%q) erro = %v, esperado erro: %v", tt.path, err, tt.wantErr)
+			}
+
+			if tt.want != nil && !reflect.DeepEqual(tt.root, tt.want) {
+				t.Errorf("Set(%q) = %#v, esperado %#v", tt.path, tt.root, tt.want)
+			}
+		})
+	}
+}
@@ -2,19 +2,28 @@ package list
 
 import (
 	"encoding/json"
-	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/maxwelbm/rabbix/pkg/output"
 	"github.com/maxwelbm/rabbix/pkg/sett"
 	"github.com/spf13/cobra"
 )
 
+// Item é um caso de teste na saída estruturada do list
+type Item struct {
+	Name     string `json:"name"`
+	File     string `json:"file"`
+	RouteKey string `json:"route_key"`
+	Exchange string `json:"exchange,omitempty"`
+	Vhost    string `json:"vhost,omitempty"`
+}
+
 func CmdList(settings sett.SettItf) *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
 		Short: "Lista todos os casos de teste salvos",
-		Run: func(_ *cobra.Command, args []string) {
+		RunE: func(_ *cobra.Command, args []string) error {
 			settings := settings.LoadSettings()
 			outputDir := settings["output_dir"]
 			if outputDir == "" {
@@ -24,11 +33,11 @@ func CmdList(settings sett.SettItf) *cobra.Command {
 
 			files, err := os.ReadDir(outputDir)
 			if err != nil {
-				fmt.Printf("Erro ao acessar diretório: %v\n", err)
-				return
+				output.Printf("Erro ao acessar diretório: %v\n", err)
+				return nil
 			}
 
-			fmt.Println("📄 Casos de teste:")
+			items := []Item{}
 
 			for _, file := range files {
 				if filepath.Ext(file.Name()) == ".json" {
@@ -43,11 +52,28 @@ func CmdList(settings sett.SettItf) *cobra.Command {
 						continue
 					}
 
-					fmt.Printf("🧪 %s  (routeKey: %s)\n",
-						file.Name(),
-						test["route_key"])
+					item := Item{File: file.Name()}
+					item.Name, _ = test["name"].(string)
+					item.RouteKey, _ = test["route_key"].(string)
+					item.Exchange, _ = test["exchange"].(string)
+					item.Vhost, _ = test["vhost"].(string)
+
+					items = append(items, item)
 				}
 			}
+
+			table := &output.Table{Header: []string{"ARQUIVO", "NOME", "ROUTE KEY", "EXCHANGE", "VHOST"}}
+			for _, item := range items {
+				table.Rows = append(table.Rows, []string{item.File, item.Name, item.RouteKey, item.Exchange, item.Vhost})
+			}
+
+			return output.Render(items, table, func() {
+				output.Println("📄 Casos de teste:")
+
+				for _, item := range items {
+					output.Printf("🧪 %s  (routeKey: %s)\n", item.File, item.RouteKey)
+				}
+			})
 		},
 	}
 }
@@ -0,0 +1,182 @@
+package mock
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// maxRepeat limita repetições abertas de regex, como '*' e '+'
+const maxRepeat = 10
+
+var (
+	firstNames = []string{
+		"Ana", "Bruno", "Carla", "Daniel", "Eduarda", "Felipe", "Gabriela", "Henrique",
+		"Isabela", "João", "Larissa", "Marcos", "Natália", "Otávio", "Paula", "Rafael",
+		"Sofia", "Thiago", "Vitória", "William",
+	}
+	lastNames = []string{
+		"Almeida", "Barbosa", "Cardoso", "Costa", "Ferreira", "Gomes", "Lima", "Martins",
+		"Oliveira", "Pereira", "Ribeiro", "Rodrigues", "Santos", "Silva", "Souza",
+	}
+	// emailReplacer remove acentos dos nomes usados no endereço de e-mail
+	emailReplacer = strings.NewReplacer("á", "a", "ã", "a", "é", "e", "í", "i", "ó", "o", "ô", "o", "ú", "u")
+)
+
+// randomString gera uma ‘string’ aleatória alfanumérica
+func randomString(n int, rng *rand.Rand) string {
+	letters := []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+
+	b := make([]rune, n)
+	for i := range b {
+		b[i] = letters[rng.Intn(len(letters))]
+	}
+
+	return string(b)
+}
+
+// uuid gera um UUID v4 a partir do rng, para respeitar o --seed
+func uuid(rng *rand.Rand) string {
+	b := make([]byte, 16)
+	_, _ = rng.Read(b)
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func name(rng *rand.Rand) string {
+	return firstNames[rng.Intn(len(firstNames))] + " " + lastNames[rng.Intn(len(lastNames))]
+}
+
+func email(rng *rand.Rand) string {
+	first := strings.ToLower(firstNames[rng.Intn(len(firstNames))])
+	last := strings.ToLower(lastNames[rng.Intn(len(lastNames))])
+
+	return emailReplacer.Replace(first+"."+last) + fmt.Sprintf("%d@example.com", rng.Intn(1000))
+}
+
+// phone gera um celular brasileiro no formato (DD) 9XXXX-XXXX
+func phone(rng *rand.Rand) string {
+	return fmt.Sprintf("(%02d) 9%04d-%04d", 11+rng.Intn(89), rng.Intn(10000), rng.Intn(10000))
+}
+
+// cpf gera um CPF válido, apenas dígitos
+func cpf(rng *rand.Rand) string {
+	digits := randomDigits(9, rng)
+	digits = append(digits, checkDigit(digits, []int{10, 9, 8, 7, 6, 5, 4, 3, 2}))
+	digits = append(digits, checkDigit(digits, []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}))
+
+	return joinDigits(digits)
+}
+
+// cnpj gera um CNPJ válido de matriz (0001), apenas dígitos
+func cnpj(rng *rand.Rand) string {
+	digits := append(randomDigits(8, rng), 0, 0, 0, 1)
+	digits = append(digits, checkDigit(digits, []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}))
+	digits = append(digits, checkDigit(digits, []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}))
+
+	return joinDigits(digits)
+}
+
+func randomDigits(n int, rng *rand.Rand) []int {
+	digits := make([]int, n)
+	for i := range digits {
+		digits[i] = rng.Intn(10)
+	}
+
+	return digits
+}
+
+// checkDigit calcula o dígito verificador módulo 11 usado por CPF e CNPJ
+func checkDigit(digits, weights []int) int {
+	sum := 0
+	for i, weight := range weights {
+		sum += digits[i] * weight
+	}
+
+	if rest := sum % 11; rest >= 2 {
+		return 11 - rest
+	}
+
+	return 0
+}
+
+func joinDigits(digits []int) string {
+	var b strings.Builder
+	for _, d := range digits {
+		b.WriteByte(byte('0' + d))
+	}
+
+	return b.String()
+}
+
+// fromRegex escreve uma string aleatória que casa com a expressão regular
+func fromRegex(b *strings.Builder, re *syntax.Regexp, rng *rand.Rand) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && rng.Intn(2) == 0 {
+				r = unicode.SimpleFold(r)
+			}
+
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		// re.Rune contém pares [início, fim] de cada intervalo da classe
+		total := 0
+		for i := 0; i < len(re.Rune); i += 2 {
+			total += int(re.Rune[i+1]-re.Rune[i]) + 1
+		}
+
+		if total == 0 {
+			return
+		}
+
+		pick := rng.Intn(total)
+		for i := 0; i < len(re.Rune); i += 2 {
+			size := int(re.Rune[i+1]-re.Rune[i]) + 1
+			if pick < size {
+				b.WriteRune(re.Rune[i] + rune(pick))
+				return
+			}
+
+			pick -= size
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune(rune('a' + rng.Intn(26)))
+	case syntax.OpCapture:
+		fromRegex(b, re.Sub[0], rng)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			fromRegex(b, sub, rng)
+		}
+	case syntax.OpAlternate:
+		fromRegex(b, re.Sub[rng.Intn(len(re.Sub))], rng)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		minCount, maxCount := repeatBounds(re)
+		for i := minCount + rng.Intn(maxCount-minCount+1); i > 0; i-- {
+			fromRegex(b, re.Sub[0], rng)
+		}
+	}
+}
+
+func repeatBounds(re *syntax.Regexp) (int, int) {
+	switch re.Op {
+	case syntax.OpStar:
+		return 0, maxRepeat
+	case syntax.OpPlus:
+		return 1, maxRepeat
+	case syntax.OpQuest:
+		return 0, 1
+	default:
+		if re.Max < 0 {
+			return re.Min, re.Min + maxRepeat
+		}
+
+		return re.Min, re.Max
+	}
+}
@@ -0,0 +1,280 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/jpath"
+)
+
+// Field é um par 'caminho:tipo' do --mock, ex: "order.items[0].sku:uuid"
+type Field struct {
+	Path string
+	Type string
+
+	generate func(rng *rand.Rand) any
+}
+
+// Parse interpreta a especificação do --mock, que pode ser um array JSON ou uma
+// lista separada por vírgulas. Tipos desconhecidos geram strings e são
+// devolvidos como avisos
+func Parse(spec string) ([]Field, []string, error) {
+	var (
+		pairs    []string
+		fields   []Field
+		warnings []string
+	)
+
+	trim := strings.TrimSpace(spec)
+	if trim == "" {
+		return nil, nil, nil
+	}
+
+	// permite JSON array ou lista separada por vírgula
+	if strings.HasPrefix(trim, "[") {
+		if err := json.Unmarshal([]byte(trim), &pairs); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Não foi possível interpretar --mock como JSON array: %v", err))
+			// tenta fallback por vírgulas removendo colchetes
+			pairs = splitPairs(strings.Trim(trim, "[]"))
+		}
+	} else {
+		pairs = splitPairs(trim)
+	}
+
+	for _, pair := range pairs {
+		// limpeza de espaços e aspas
+		pair = strings.Trim(pair, " \"\n\t")
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, nil, fmt.Errorf("par inválido em --mock: '%s' (esperado 'campo:tipo')", pair)
+		}
+
+		field := Field{
+			Path: strings.TrimSpace(parts[0]),
+			Type: strings.TrimSpace(parts[1]),
+		}
+
+		if _, err := jpath.Parse(field.Path); err != nil {
+			return nil, nil, err
+		}
+
+		generate, known, err := generator(field.Type)
+		if err != nil {
+			return nil, nil, fmt.Errorf("campo '%s': %w", field.Path, err)
+		}
+
+		if !known {
+			warnings = append(warnings,
+				fmt.Sprintf("Tipo desconhecido '%s' para campo '%s'. Usando string.", field.Type, field.Path))
+		}
+
+		field.generate = generate
+		fields = append(fields, field)
+	}
+
+	return fields, warnings, nil
+}
+
+// Apply gera um novo valor para cada campo e grava no payload
+func Apply(pool map[string]any, fields []Field, rng *rand.Rand) error {
+	for _, field := range fields {
+		if err := jpath.Set(pool, field.Path, field.generate(rng)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f Field) String() string {
+	return f.Path + ":" + f.Type
+}
+
+// splitPairs separa por vírgulas que não estejam dentro de parênteses, para
+// preservar argumentos como "int(1,10)" e "regex([a-z]{2,4})"
+func splitPairs(spec string) []string {
+	var (
+		pairs []string
+		depth int
+		start int
+	)
+
+	for i, r := range spec {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				pairs = append(pairs, spec[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(pairs, spec[start:])
+}
+
+// generator resolve o tipo, com argumentos opcionais entre parênteses, para a
+// função geradora. O segundo retorno indica se o tipo é conhecido
+func generator(typeSpec string) (func(rng *rand.Rand) any, bool, error) {
+	typeName, args, hasArgs := strings.Cut(typeSpec, "(")
+	typeName = strings.ToLower(strings.TrimSpace(typeName))
+
+	if hasArgs {
+		if !strings.HasSuffix(args, ")") {
+			return nil, false, fmt.Errorf("tipo '%s' sem ')' de fechamento", typeSpec)
+		}
+
+		args = strings.TrimSuffix(args, ")")
+	}
+
+	switch typeName {
+	case "int":
+		minValue, maxValue := 0, 999999
+		if hasArgs {
+			var err error
+			if minValue, maxValue, err = intRange(args); err != nil {
+				return nil, false, err
+			}
+		}
+
+		return func(rng *rand.Rand) any {
+			return minValue + rng.Intn(maxValue-minValue+1)
+		}, true, nil
+	case "float", "float64":
+		minValue, maxValue := 0.0, 100000.0
+		if hasArgs {
+			var err error
+			if minValue, maxValue, err = floatRange(args); err != nil {
+				return nil, false, err
+			}
+		}
+
+		return func(rng *rand.Rand) any {
+			return minValue + rng.Float64()*(maxValue-minValue)
+		}, true, nil
+	case "string":
+		n := 12
+		if hasArgs {
+			var err error
+			if n, err = strconv.Atoi(strings.TrimSpace(args)); err != nil || n < 0 {
+				return nil, false, fmt.Errorf("tamanho inválido em '%s'", typeSpec)
+			}
+		}
+
+		return func(rng *rand.Rand) any { return randomString(n, rng) }, true, nil
+	case "time", "datetime", "date":
+		return func(*rand.Rand) any { return time.Now().Format(time.RFC3339) }, true, nil
+	case "bool", "boolean":
+		return func(rng *rand.Rand) any { return rng.Intn(2) == 0 }, true, nil
+	case "uuid":
+		return func(rng *rand.Rand) any { return uuid(rng) }, true, nil
+	case "name":
+		return func(rng *rand.Rand) any { return name(rng) }, true, nil
+	case "email":
+		return func(rng *rand.Rand) any { return email(rng) }, true, nil
+	case "cpf":
+		return func(rng *rand.Rand) any { return cpf(rng) }, true, nil
+	case "cnpj":
+		return func(rng *rand.Rand) any { return cnpj(rng) }, true, nil
+	case "phone":
+		return func(rng *rand.Rand) any { return phone(rng) }, true, nil
+	case "enum":
+		options := strings.Split(args, "|")
+		if !hasArgs || strings.TrimSpace(args) == "" {
+			return nil, false, fmt.Errorf("enum exige opções, ex: enum(A|B|C)")
+		}
+
+		return func(rng *rand.Rand) any { return options[rng.Intn(len(options))] }, true, nil
+	case "regex":
+		if !hasArgs || args == "" {
+			return nil, false, fmt.Errorf("regex exige um padrão, ex: regex([A-Z]{3}-[0-9]{4})")
+		}
+
+		re, err := syntax.Parse(args, syntax.Perl)
+		if err != nil {
+			return nil, false, fmt.Errorf("regex inválida '%s': %w", args, err)
+		}
+
+		re = re.Simplify()
+
+		return func(rng *rand.Rand) any {
+			var b strings.Builder
+			fromRegex(&b, re, rng)
+
+			return b.String()
+		}, true, nil
+	default:
+		return func(rng *rand.Rand) any { return randomString(8, rng) }, false, nil
+	}
+}
+
+func intRange(args string) (int, int, error) {
+	minArg, maxArg, ok := strings.Cut(args, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("intervalo inválido '%s' (esperado 'min,max')", args)
+	}
+
+	minValue, err := strconv.Atoi(strings.TrimSpace(minArg))
+	if err != nil {
+		return 0, 0, fmt.Errorf("mínimo inválido '%s'", minArg)
+	}
+
+	maxValue, err := strconv.Atoi(strings.TrimSpace(maxArg))
+	if err != nil {
+		return 0, 0, fmt.Errorf("máximo inválido '%s'", maxArg)
+	}
+
+	if maxValue < minValue {
+		return 0, 0, fmt.Errorf("máximo %d menor que o mínimo %d", maxValue, minValue)
+	}
+
+	// A largura max-min+1 precisa caber em um int para o rng.Intn
+	if uint64(maxValue)-uint64(minValue) >= math.MaxInt64 {
+		return 0, 0, fmt.Errorf("intervalo de %d a %d grande demais", minValue, maxValue)
+	}
+
+	return minValue, maxValue, nil
+}
+
+func floatRange(args string) (float64, float64, error) {
+	minArg, maxArg, ok := strings.Cut(args, ",")
+	if !ok {
+		return 0, 0, fmt.Errorf("intervalo inválido '%s' (esperado 'min,max')", args)
+	}
+
+	minValue, err := strconv.ParseFloat(strings.TrimSpace(minArg), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("mínimo inválido '%s'", minArg)
+	}
+
+	maxValue, err := strconv.ParseFloat(strings.TrimSpace(maxArg), 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("máximo inválido '%s'", maxArg)
+	}
+
+	if maxValue < minValue {
+		return 0, 0, fmt.Errorf("máximo %v menor que o mínimo %v", maxValue, minValue)
+	}
+
+	// Também rejeita NaN e infinitos, inclusive na largura do intervalo
+	if width := maxValue - minValue; math.IsNaN(width) || math.IsInf(width, 0) {
+		return 0, 0, fmt.Errorf("intervalo de %v a %v inválido", minValue, maxValue)
+	}
+
+	return minValue, maxValue, nil
+}
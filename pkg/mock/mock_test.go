@@ -0,0 +1,222 @@
+package mock
+
+import (
+	"math/rand"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         string
+		want         []string
+		wantWarnings int
+		wantErr      string
+	}{
+		{name: "vazio", spec: " "},
+		{name: "lista", spec: "id:int, name:name", want: []string{"id:int", "name:name"}},
+		{
+			name: "array json",
+			spec: `["id:uuid","order.total:float(1,2)"]`,
+			want: []string{"id:uuid", "order.total:float(1,2)"},
+		},
+		{
+			name: "vírgulas dentro de parênteses",
+			spec: "qty:int(1,10),code:regex([A-Z]{2,4})",
+			want: []string{"qty:int(1,10)", "code:regex([A-Z]{2,4})"},
+		},
+		{name: "tipo desconhecido", spec: "id:xpto", want: []string{"id:xpto"}, wantWarnings: 1},
+		{name: "json inválido usa vírgulas", spec: "[id:int]", want: []string{"id:int"}, wantWarnings: 1},
+		{name: "sem tipo", spec: "id", wantErr: "par inválido"},
+		{name: "sem campo", spec: ":int", wantErr: "par inválido"},
+		{name: "caminho inválido", spec: "items[x]:int", wantErr: "caminho inválido"},
+		{name: "parêntese sem fechar", spec: "id:int(1,2", wantErr: "sem ')'"},
+		{name: "int sem vírgula", spec: "id:int(5)", wantErr: "intervalo inválido"},
+		{name: "int máximo menor", spec: "id:int(10,1)", wantErr: "menor que o mínimo"},
+		{name: "int largo demais", spec: "id:int(-9223372036854775808,9223372036854775807)", wantErr: "grande demais"},
+		{name: "float invertido", spec: "id:float(2,1)", wantErr: "menor que o mínimo"},
+		{name: "float infinito", spec: "id:float(0,Inf)", wantErr: "inválido"},
+		{name: "string negativa", spec: "id:string(-1)", wantErr: "tamanho inválido"},
+		{name: "enum vazio", spec: "id:enum()", wantErr: "enum exige opções"},
+		{name: "regex vazia", spec: "id:regex()", wantErr: "regex exige um padrão"},
+		{name: "regex inválida", spec: "id:regex([a-)", wantErr: "regex inválida"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, warnings, err := Parse(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse(%q) erro = %v, esperado contendo %q", tt.spec, err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse(%q) erro inesperado: %v", tt.spec, err)
+			}
+
+			var got []string
+			for _, field := range fields {
+				got = append(got, field.String())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %q, esperado %q", tt.spec, got, tt.want)
+			}
+
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("Parse(%q) avisos = %q, esperado %d", tt.spec, warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		typeSpec string
+		check    func(value any) bool
+	}{
+		{typeSpec: "int(-2,2)", check: func(v any) bool { n, ok := v.(int); return ok && n >= -2 && n <= 2 }},
+		{typeSpec: "int(7,7)", check: func(v any) bool { return v == 7 }},
+		{
+			typeSpec: "int(-9223372036854775807,-1)",
+			check:    func(v any) bool { n, ok := v.(int); return ok && n < 0 },
+		},
+		{typeSpec: "float(1.5,2)", check: func(v any) bool { f, ok := v.(float64); return ok && f >= 1.5 && f <= 2 }},
+		{typeSpec: "string(5)", check: matches(`^[a-zA-Z0-9]{5}$`)},
+		{typeSpec: "bool", check: func(v any) bool { _, ok := v.(bool); return ok }},
+		{typeSpec: "uuid", check: matches(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)},
+		{typeSpec: "email", check: matches(`^[a-z]+\.[a-z]+[0-9]{1,3}@example\.com$`)},
+		{typeSpec: "phone", check: matches(`^\([1-9][0-9]\) 9[0-9]{4}-[0-9]{4}$`)},
+		{typeSpec: "name", check: matches(`^\S+ \S+$`)},
+		{typeSpec: "cpf", check: validDocument(9)},
+		{typeSpec: "cnpj", check: validDocument(12)},
+		{typeSpec: "enum(A|B|C)", check: func(v any) bool { return v == "A" || v == "B" || v == "C" }},
+		{typeSpec: "date", check: matches(`^\d{4}-\d{2}-\d{2}T`)},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+
+	for _, tt := range tests {
+		t.Run(tt.typeSpec, func(t *testing.T) {
+			generate, known, err := generator(tt.typeSpec)
+			if err != nil || !known {
+				t.Fatalf("generator(%q) = conhecido %v, erro %v", tt.typeSpec, known, err)
+			}
+
+			for range 100 {
+				if value := generate(rng); !tt.check(value) {
+					t.Fatalf("generator(%q) gerou %#v", tt.typeSpec, value)
+				}
+			}
+		})
+	}
+}
+
+func TestRegexGenerator(t *testing.T) {
+	patterns := []string{
+		`[A-Z]{3}-[0-9]{4}`,
+		`(PED|ORD)_\d{2,5}`,
+		`[a-f0-9]+`,
+		`x*y?z`,
+		`(?i)abc`,
+		`a.c`,
+		`\w{1,3}@[a-z]{2,}\.com`,
+	}
+
+	rng := rand.New(rand.NewSource(1))
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			generate, _, err := generator("regex(" + pattern + ")")
+			if err != nil {
+				t.Fatalf("generator(regex(%s)) erro inesperado: %v", pattern, err)
+			}
+
+			re := regexp.MustCompile(`^(?:` + pattern + `)$`)
+
+			for range 100 {
+				value := generate(rng).(string)
+				if !re.MatchString(value) {
+					t.Fatalf("regex(%s) gerou %q, que não casa com o padrão", pattern, value)
+				}
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	fields, _, err := Parse("order.items[1].sku:enum(X),order.qty:int(3,3)")
+	if err != nil {
+		t.Fatalf("Parse erro inesperado: %v", err)
+	}
+
+	pool := map[string]any{"order": map[string]any{"id": 1}}
+	if err := Apply(pool, fields, rand.New(rand.NewSource(1))); err != nil {
+		t.Fatalf("Apply erro inesperado: %v", err)
+	}
+
+	want := map[string]any{"order": map[string]any{
+		"id":    1,
+		"qty":   3,
+		"items": []any{nil, map[string]any{"sku": "X"}},
+	}}
+
+	if !reflect.DeepEqual(pool, want) {
+		t.Errorf("Apply = %#v, esperado %#v", pool, want)
+	}
+
+	fields, _, _ = Parse("id.sku:int")
+	if err := Apply(map[string]any{"id": []any{}}, fields, rand.New(rand.NewSource(1))); err == nil {
+		t.Error("Apply em caminho incompatível deveria falhar")
+	}
+}
+
+func matches(pattern string) func(value any) bool {
+	re := regexp.MustCompile(pattern)
+
+	return func(value any) bool {
+		s, ok := value.(string)
+		return ok && re.MatchString(s)
+	}
+}
+
+// validDocument confere os dois dígitos verificadores módulo 11 de CPF e CNPJ,
+// calculados aqui de forma independente de checkDigit
+func validDocument(base int) func(value any) bool {
+	return func(value any) bool {
+		s, ok := value.(string)
+		if !ok || len(s) != base+2 {
+			return false
+		}
+
+		for n := base; n < base+2; n++ {
+			sum := 0
+			for i := range n {
+				// Pesos decrescentes a partir de n+1, reiniciando em 9 no CNPJ
+				weight := n + 1 - i
+				if base == 12 {
+					weight = (n-i-1)%8 + 2
+				}
+
+				sum += int(s[i]-'0') * weight
+			}
+
+			digit := 0
+			if rest := sum % 11; rest >= 2 {
+				digit = 11 - rest
+			}
+
+			if int(s[n]-'0') != digit {
+				return false
+			}
+		}
+
+		return true
+	}
+}
@@ -0,0 +1,248 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FormatText é a saída padrão, com textos e emojis para leitura humana
+	FormatText = "text"
+	// FormatJSON serializa o resultado do comando em JSON
+	FormatJSON = "json"
+	// FormatYAML serializa o resultado do comando em YAML
+	FormatYAML = "yaml"
+	// FormatTable exibe o resultado do comando em uma tabela alinhada
+	FormatTable = "table"
+)
+
+var (
+	format  = FormatText
+	plain   bool
+	noColor bool
+
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// Table é a representação tabular do resultado de um comando
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Register adiciona as flags globais de saída ao comando raiz
+func Register(flags *pflag.FlagSet) {
+	flags.StringVarP(&format, "output", "o", FormatText,
+		"Formato da saída: text, json, yaml ou table")
+	flags.BoolVar(&plain, "plain", false,
+		"Remove emojis da saída de texto, útil para logs")
+	flags.BoolVar(&noColor, "no-color", false,
+		"Equivalente a --plain")
+}
+
+// Validate verifica o formato informado em --output
+func Validate() error {
+	format = strings.ToLower(strings.TrimSpace(format))
+
+	switch format {
+	case FormatText, FormatJSON, FormatYAML, FormatTable:
+		return nil
+	default:
+		return fmt.Errorf("formato de saída desconhecido '%s' (use %s, %s, %s ou %s)",
+			format, FormatText, FormatJSON, FormatYAML, FormatTable)
+	}
+}
+
+// Format retorna o formato de saída ativo
+func Format() string {
+	return format
+}
+
+// Plain indica se os emojis devem ser removidos, por --plain, --no-color ou NO_COLOR
+func Plain() bool {
+	return plain || noColor || os.Getenv("NO_COLOR") != ""
+}
+
+// Printf escreve mensagens para leitura humana. Fora do formato text elas vão
+// para a saída de erro, para não misturar com o resultado estruturado
+func Printf(format string, args ...any) {
+	write(fmt.Sprintf(format, args...))
+}
+
+// Println é o equivalente de fmt.Println para mensagens para leitura humana
+func Println(args ...any) {
+	write(fmt.Sprintln(args...))
+}
+
+// Render emite o resultado do comando no formato ativo: json e yaml serializam
+// data, table usa a tabela (ou data, quando ela é nil) e text executa a função
+// com a saída tradicional do comando
+func Render(data any, table *Table, text func()) error {
+	switch format {
+	case FormatJSON:
+		encoded, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("erro ao serializar saída em JSON: %w", err)
+		}
+
+		_, err = fmt.Fprintln(stdout, string(encoded))
+
+		return err
+	case FormatYAML:
+		encoded, err := toYAML(data)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar saída em YAML: %w", err)
+		}
+
+		_, err = stdout.Write(encoded)
+
+		return err
+	case FormatTable:
+		if table == nil {
+			return Render(data, nil, nil)
+		}
+
+		return writeTable(table)
+	default:
+		if text != nil {
+			text()
+		}
+
+		return nil
+	}
+}
+
+// Milliseconds converte a duração para os campos *_ms dos resultados, com
+// precisão de microssegundos
+func Milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func write(text string) {
+	if Plain() {
+		text = stripEmoji(text)
+	}
+
+	if format == FormatText {
+		_, _ = io.WriteString(stdout, text)
+		return
+	}
+
+	_, _ = io.WriteString(stderr, text)
+}
+
+func writeTable(table *Table) error {
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+
+	if len(table.Header) > 0 {
+		if _, err := fmt.Fprintln(w, strings.Join(table.Header, "\t")); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range table.Rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			// quebras de linha e tabs desalinhariam a tabela
+			cells[i] = strings.NewReplacer("\n", " ", "\t", " ").Replace(cell)
+		}
+
+		if _, err := fmt.Fprintln(w, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// toYAML converte via JSON para respeitar as tags json e a ordem dos campos
+func toYAML(data any) ([]byte, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(encoded, &node); err != nil {
+		return nil, err
+	}
+
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// blockStyle remove o estilo JSON (flow e aspas) herdado do parse
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// stripEmoji remove emojis e os espaços que os seguem no início das linhas
+func stripEmoji(text string) string {
+	lines := strings.SplitAfter(text, "\n")
+
+	for i, line := range lines {
+		var b strings.Builder
+
+		stripped := false
+
+		for _, r := range line {
+			if isEmoji(r) {
+				stripped = true
+				continue
+			}
+
+			b.WriteRune(r)
+		}
+
+		if !stripped {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		lines[i] = strings.Repeat(" ", indent) + strings.TrimLeft(b.String(), " ")
+	}
+
+	return strings.Join(lines, "")
+}
+
+func isEmoji(r rune) bool {
+	switch {
+	case r == 0xFE0F || r == 0x200D: // seletor de variação e zero width joiner
+		return true
+	case r >= 0x2500 && r <= 0x257F: // mantém os separadores de caixa
+		return false
+	case r >= 0x1F000 && r <= 0x1FAFF:
+		return true
+	default:
+		return unicode.Is(unicode.So, r)
+	}
+}
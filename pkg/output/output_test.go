@@ -0,0 +1,30 @@
+package output
+
+import "testing"
+
+func TestStripEmoji(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "sem emoji", text: "Teste enviado\n", want: "Teste enviado\n"},
+		{name: "emoji no início", text: "✅ Teste enviado\n", want: "Teste enviado\n"},
+		{name: "emoji com seletor de variação", text: "⚠️  Resumo parcial\n", want: "Resumo parcial\n"},
+		{name: "emoji no meio", text: "📊 Linhas: 3 | ✅ Publicadas: 2\n", want: "Linhas: 3 |  Publicadas: 2\n"},
+		{name: "mantém a indentação", text: "   ❌ linha 2: erro\n", want: "   linha 2: erro\n"},
+		{name: "várias linhas", text: "🔍 Falhas:\n  • a\n", want: "Falhas:\n  • a\n"},
+		{name: "mantém separadores de caixa", text: "─────\n", want: "─────\n"},
+		{name: "sequência com zero width joiner", text: "👩‍💻 dev\n", want: "dev\n"},
+		{name: "sem quebra de linha final", text: "🚀 Enviando", want: "Enviando"},
+		{name: "vazio", text: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripEmoji(tt.text); got != tt.want {
+				t.Errorf("stripEmoji(%q) = %q, esperado %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,484 @@
+package publish
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/interrupt"
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	"github.com/maxwelbm/rabbix/pkg/request"
+	"github.com/maxwelbm/rabbix/pkg/tmpl"
+	"github.com/spf13/cobra"
+)
+
+// maxLineSize é o tamanho máximo de uma linha do JSON Lines
+const maxLineSize = 16 * 1024 * 1024
+
+type Publish struct {
+	request request.RequestItf
+}
+
+func New(request request.RequestItf) *Publish {
+	return &Publish{
+		request: request,
+	}
+}
+
+// options são as flags do publish
+type options struct {
+	routeKey        string
+	exchange        string
+	vhost           string
+	transport       string
+	concurrency     int
+	delay           int
+	allowUnroutable bool
+	render          bool
+	retry           request.RetryPolicy
+}
+
+// line é uma linha não vazia do JSON Lines, com o número da linha no arquivo
+type line struct {
+	number int
+	data   []byte
+}
+
+func (p *Publish) CmdPublish() *cobra.Command {
+	var (
+		opts           options
+		overallTimeout time.Duration
+	)
+
+	var cmd = &cobra.Command{
+		Use:   "publish [arquivo.jsonl]",
+		Short: "Publica mensagens lidas em JSON Lines da entrada padrão ou de um arquivo",
+		Long: `Publica uma mensagem por linha de um arquivo JSON Lines ou da entrada padrão ('-'
+ou sem argumento), na ordem das linhas. Cada linha é um registro no formato do caso de
+teste (route_key, exchange, vhost, json_pool, payload, headers, properties); com
+--route-key cada linha é o próprio payload, publicado como está.
+Os registros são publicados como estão; com --render os placeholders {{...}} são
+avaliados como no run, inclusive {{env "NOME"}}.
+Linhas inválidas e publicações com falha não interrompem o envio: elas são listadas
+ao final com o número da linha. Linhas em branco são ignoradas.
+Ctrl+C para a leitura de novas linhas e aguarda as publicações em andamento.
+Exemplos:
+  rabbix publish mensagens.jsonl
+  jq -c '.pedidos[] | {route_key: "pedidos.criado", json_pool: .}' export.json | rabbix publish
+  cat eventos.jsonl | rabbix publish --route-key eventos.importados -c 5
+  rabbix publish modelos.jsonl --render
+  rabbix publish mensagens.jsonl --exchange amq.topic --timeout 5s -o json`,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return []string{"jsonl", "ndjson"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.retry.Validate(); err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
+			if opts.concurrency < 1 {
+				return exitcode.New(exitcode.Config, "--concurrency deve ser maior que zero")
+			}
+
+			if overallTimeout < 0 {
+				return exitcode.New(exitcode.Config, "--overall-timeout não pode ser negativo")
+			}
+
+			source := "-"
+			if len(args) > 0 {
+				source = args[0]
+			}
+
+			input, err := open(cmd, source)
+			if err != nil {
+				return err
+			}
+
+			defer func() {
+				_ = input.Close()
+			}()
+
+			output.Printf("🚀 Publicando mensagens de %s\n", displaySource(source))
+
+			if opts.routeKey != "" {
+				output.Printf("📤 Route Key: %s (cada linha é o payload)\n", opts.routeKey)
+			}
+
+			output.Printf("⚙️  Concorrência: %d | Delay: %dms\n", opts.concurrency, opts.delay)
+			output.Println("─────────────────────────────────────")
+
+			// Mantém a conexão do transporte aberta durante todo o envio
+			defer func() {
+				if err := p.request.Close(); err != nil {
+					output.Printf("❌ Erro ao encerrar conexão: %v\n", err)
+				}
+			}()
+
+			// Ctrl+C para a leitura; o tempo total cancela também as publicações em andamento
+			scope := interrupt.New(cmd.Context(), overallTimeout)
+			defer scope.Close()
+
+			startTime := time.Now()
+			summary := p.execute(scope, input, opts)
+			summary.Source = displaySource(source)
+			summary.DurationMs = output.Milliseconds(time.Since(startTime))
+
+			interrupted := scope.Err()
+			if interrupted != nil {
+				summary.Interrupted = interrupted.Error()
+			}
+
+			err = output.Render(summary, summary.table(), func() {
+				output.Println("─────────────────────────────────────")
+				output.Printf("📊 Linhas: %d | ✅ Publicadas: %d | ❌ Falhas: %d | ⏱️  %v\n",
+					summary.Total, summary.Published, summary.Failed, time.Since(startTime).Round(time.Millisecond))
+
+				if interrupted != nil {
+					output.Printf("⚠️  Resumo parcial (%v)\n", interrupted)
+				}
+
+				if len(summary.Failures) > 0 {
+					output.Println("\n🔍 Falhas por linha:")
+
+					for _, failure := range summary.Failures {
+						output.Printf("  • linha %d: %s\n", failure.Line, failure.Error)
+					}
+				}
+			})
+			if err != nil {
+				return err
+			}
+
+			if interrupted != nil {
+				return interrupted
+			}
+
+			// Um erro de leitura deixa parte da entrada sem publicar, mesmo que as
+			// linhas lidas tenham sido publicadas
+			switch {
+			case summary.Total == 0 && summary.ReadError != "":
+				return exitcode.New(exitcode.Config, "erro ao ler %s: %s", displaySource(source), summary.ReadError)
+			case summary.Total == 0:
+				return exitcode.New(exitcode.Config, "nenhuma linha para publicar em %s", displaySource(source))
+			case summary.Failed == summary.Total:
+				return exitcode.New(exitcode.Publish, "nenhuma das %d linha(s) foi publicada", summary.Total)
+			case summary.Failed > 0:
+				return exitcode.New(exitcode.Partial, "%d de %d linha(s) falharam", summary.Failed, summary.Total)
+			case summary.ReadError != "":
+				return exitcode.New(exitcode.Partial,
+					"leitura interrompida após %d linha(s): %s", summary.Total, summary.ReadError)
+			default:
+				return nil
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.routeKey, "route-key", "",
+		"Publica cada linha como payload, como está, com esta route key")
+	cmd.Flags().StringVar(&opts.exchange, "exchange", "",
+		"Exchange de destino (sobrescreve o dos registros e o da configuração)")
+	cmd.Flags().StringVar(&opts.vhost, "vhost", "",
+		"Virtual host de destino (sobrescreve o dos registros e o da configuração)")
+	cmd.Flags().StringVar(&opts.transport, "transport", "",
+		"Transporte de publicação: http (API de gerenciamento) ou amqp")
+	cmd.Flags().IntVarP(&opts.concurrency, "concurrency", "c", 1,
+		"Número máximo de publicações simultâneas; as linhas sempre iniciam em ordem")
+	cmd.Flags().IntVarP(&opts.delay, "delay", "d", 0,
+		"Delay em milissegundos entre publicações (0 = sem delay)")
+	cmd.Flags().BoolVar(&opts.allowUnroutable, "allow-unroutable", false,
+		"Considera sucesso mensagens publicadas que não foram roteadas para nenhuma fila")
+	cmd.Flags().BoolVar(&opts.render, "render", false,
+		"Avalia os placeholders {{...}} dos registros antes de publicar")
+	opts.retry.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&overallTimeout, "overall-timeout", 0,
+		"Tempo máximo do envio completo; publicações em andamento são canceladas (0 = sem limite)")
+
+	return cmd
+}
+
+// open abre o arquivo ou a entrada padrão, que não pode ser um terminal
+func open(cmd *cobra.Command, source string) (io.ReadCloser, error) {
+	if source != "-" {
+		file, err := os.Open(source)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, exitcode.New(exitcode.NotFound, "arquivo '%s' não encontrado", source)
+		}
+
+		if err != nil {
+			return nil, exitcode.New(exitcode.Config, "erro ao abrir '%s': %w", source, err)
+		}
+
+		return file, nil
+	}
+
+	if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+		return nil, exitcode.New(exitcode.Config,
+			"informe um arquivo JSON Lines ou envie as linhas pela entrada padrão (ex: cat msgs.jsonl | rabbix publish)")
+	}
+
+	return io.NopCloser(cmd.InOrStdin()), nil
+}
+
+// execute lê as linhas e as publica em ordem, com no máximo opts.concurrency
+// publicações simultâneas. A leitura acontece em outra goroutine para que
+// Ctrl+C interrompa mesmo enquanto a entrada padrão está aguardando dados
+func (p *Publish) execute(scope *interrupt.Scope, input io.Reader, opts options) Summary {
+	var (
+		summary Summary
+		mutex   sync.Mutex
+		wg      sync.WaitGroup
+	)
+
+	record := func(result LineResult) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		summary.add(result)
+	}
+
+	lines, readErr := read(input)
+	semaphore := make(chan struct{}, opts.concurrency)
+
+	// Compartilhado entre as goroutines para que {{seq}} avance a cada mensagem
+	engine := tmpl.New()
+	delay := time.Duration(opts.delay) * time.Millisecond
+	started := 0
+	eof := false
+
+dispatch:
+	for {
+		var current line
+
+		select {
+		case next, ok := <-lines:
+			if !ok {
+				eof = true
+				break dispatch
+			}
+
+			current = next
+		case <-scope.Schedule.Done():
+			break dispatch
+		}
+
+		tc, err := parse(current, opts)
+		if err != nil {
+			output.Printf("❌ [linha %d] %v\n", current.number, err)
+			record(LineResult{Line: current.number, Error: err.Error()})
+
+			continue
+		}
+
+		// Adquire o semáforo antes de iniciar a goroutine para manter a ordem das linhas
+		select {
+		case semaphore <- struct{}{}:
+		case <-scope.Schedule.Done():
+			break dispatch
+		}
+
+		if started > 0 && !scope.Sleep(delay) {
+			<-semaphore
+			break dispatch
+		}
+
+		started++
+
+		wg.Add(1)
+
+		go func(number int, tc rabbix.TestCase) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			record(p.publish(scope, number, tc, engine, opts))
+		}(current.number, tc)
+	}
+
+	wg.Wait()
+
+	// Após uma interrupção a leitura fica para trás, sem aguardar o fim da entrada
+	if eof {
+		if err := <-readErr; err != nil {
+			output.Printf("❌ Erro ao ler a entrada: %v\n", err)
+			summary.ReadError = err.Error()
+		}
+	}
+
+	summary.sort()
+
+	return summary
+}
+
+// read envia as linhas não vazias pelo canal e o erro de leitura, se houver,
+// ao final. A leitura segue em segundo plano até o fim da entrada
+func read(input io.Reader) (<-chan line, <-chan error) {
+	lines := make(chan line)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(lines)
+
+		scanner := bufio.NewScanner(input)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+		for number := 1; scanner.Scan(); number++ {
+			data := bytes.TrimSpace(scanner.Bytes())
+			if len(data) == 0 {
+				continue
+			}
+
+			lines <- line{number: number, data: bytes.Clone(data)}
+		}
+
+		errs <- scanner.Err()
+	}()
+
+	return lines, errs
+}
+
+// parse converte a linha em um caso de teste: um registro completo ou, com
+// --route-key, o próprio payload
+func parse(current line, opts options) (rabbix.TestCase, error) {
+	var tc rabbix.TestCase
+
+	if !json.Valid(current.data) {
+		return tc, fmt.Errorf("JSON inválido")
+	}
+
+	if opts.routeKey != "" {
+		tc = rabbix.TestCase{
+			RouteKey: opts.routeKey,
+			Payload:  &rabbix.Payload{JSON: json.RawMessage(current.data)},
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(current.data))
+		decoder.DisallowUnknownFields()
+
+		if err := decoder.Decode(&tc); err != nil {
+			return tc, fmt.Errorf("registro inválido: %w", err)
+		}
+
+		if tc.Expect != nil || len(tc.Capture) > 0 {
+			return tc, fmt.Errorf("'expect' e 'capture' não são suportados no publish, use 'rabbix batch'")
+		}
+	}
+
+	if tc.Name == "" {
+		tc.Name = fmt.Sprintf("linha-%d", current.number)
+	}
+
+	if opts.exchange != "" {
+		tc.Exchange = opts.exchange
+	}
+
+	if opts.vhost != "" {
+		tc.Vhost = opts.vhost
+	}
+
+	if opts.transport != "" {
+		tc.Transport = opts.transport
+	}
+
+	if err := tc.Validate(); err != nil {
+		return tc, err
+	}
+
+	return tc, nil
+}
+
+// publish publica a mensagem de uma linha e monta o resultado
+func (p *Publish) publish(
+	scope *interrupt.Scope,
+	number int,
+	tc rabbix.TestCase,
+	engine *tmpl.Engine,
+	opts options,
+) LineResult {
+	start := time.Now()
+	result := LineResult{Line: number, Name: tc.Name, RouteKey: tc.RouteKey}
+
+	fail := func(err error) LineResult {
+		result.Error = err.Error()
+		result.DurationMs = output.Milliseconds(time.Since(start))
+		output.Printf("❌ [linha %d] %v\n", number, err)
+
+		return result
+	}
+
+	msg := tc
+
+	if opts.render {
+		var err error
+		if msg, err = engine.RenderTestCase(tc); err != nil {
+			return fail(fmt.Errorf("erro ao avaliar placeholders: %w", err))
+		}
+	}
+
+	resp, attempts, err := request.Publish(scope.Work, p.request, msg, opts.retry,
+		func(attempt int, reason string, wait time.Duration) {
+			output.Printf("🔁 [linha %d] tentativa %d/%d falhou (%s), nova tentativa em %v\n",
+				number, attempt, opts.retry.MaxAttempts, reason, wait.Round(time.Millisecond))
+		})
+	result.Attempts = attempts
+
+	if err != nil {
+		return fail(err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fail(fmt.Errorf("erro ao ler resposta: %w", err))
+	}
+
+	result.Status = resp.StatusCode
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fail(fmt.Errorf("status HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(body)))
+	}
+
+	published, err := request.ParseResult(body)
+	if err != nil {
+		return fail(err)
+	}
+
+	result.Routed = published.Routed
+
+	if !published.Routed && !opts.allowUnroutable {
+		return fail(fmt.Errorf("mensagem não roteada para nenhuma fila (verifique route key e exchange)"))
+	}
+
+	result.Success = true
+	result.DurationMs = output.Milliseconds(time.Since(start))
+
+	output.Printf("✅ [linha %d] %s: OK (Status: %d, Roteada: %t)\n", number, tc.RouteKey, resp.StatusCode, result.Routed)
+
+	return result
+}
+
+func displaySource(source string) string {
+	if source == "-" {
+		return "entrada padrão"
+	}
+
+	return source
+}
@@ -0,0 +1,76 @@
+package publish
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/maxwelbm/rabbix/pkg/output"
+)
+
+// Summary é o resultado do publish na saída estruturada (--output json|yaml|table).
+// Apenas as linhas com falha são listadas, já que a entrada pode ser longa
+type Summary struct {
+	Source     string  `json:"source"`
+	Total      int     `json:"total"`
+	Published  int     `json:"published"`
+	Failed     int     `json:"failed"`
+	DurationMs float64 `json:"duration_ms"`
+	// Interrupted traz o motivo quando o envio foi interrompido antes do fim da entrada
+	Interrupted string `json:"interrupted,omitempty"`
+	// ReadError traz o erro que impediu a leitura do restante da entrada
+	ReadError string       `json:"read_error,omitempty"`
+	Failures  []LineResult `json:"failures"`
+}
+
+// LineResult é o resultado da publicação de uma linha
+type LineResult struct {
+	Line       int     `json:"line"`
+	Name       string  `json:"name,omitempty"`
+	RouteKey   string  `json:"route_key,omitempty"`
+	Success    bool    `json:"success"`
+	Status     int     `json:"status,omitempty"`
+	Routed     bool    `json:"routed"`
+	Attempts   int     `json:"attempts,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+func (s *Summary) add(result LineResult) {
+	s.Total++
+
+	if result.Success {
+		s.Published++
+		return
+	}
+
+	s.Failed++
+	s.Failures = append(s.Failures, result)
+}
+
+// sort ordena as falhas pela linha, já que as publicações terminam fora de ordem
+func (s *Summary) sort() {
+	if s.Failures == nil {
+		s.Failures = []LineResult{}
+	}
+
+	sort.Slice(s.Failures, func(i, j int) bool {
+		return s.Failures[i].Line < s.Failures[j].Line
+	})
+}
+
+func (s *Summary) table() *output.Table {
+	table := &output.Table{Header: []string{"LINHA", "ROUTE KEY", "STATUS", "TENTATIVAS", "DURAÇÃO (ms)", "ERRO"}}
+
+	for _, failure := range s.Failures {
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(failure.Line),
+			failure.RouteKey,
+			strconv.Itoa(failure.Status),
+			strconv.Itoa(failure.Attempts),
+			strconv.FormatFloat(failure.DurationMs, 'f', 3, 64),
+			failure.Error,
+		})
+	}
+
+	return table
+}
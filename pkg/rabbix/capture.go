@@ -0,0 +1,107 @@
+package rabbix
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/maxwelbm/rabbix/pkg/jpath"
+)
+
+const (
+	// CapturePayload captura do payload publicado, já com placeholders e --mock aplicados
+	CapturePayload = "payload"
+	// CaptureHeaders captura dos headers publicados
+	CaptureHeaders = "headers"
+	// CaptureReply captura do payload da resposta no modo request/reply
+	CaptureReply = "reply"
+)
+
+// Capture é uma regra que guarda um valor da mensagem em uma variável,
+// disponível nas mensagens seguintes como {{vars.nome}}. No JSON também aceita
+// a forma curta "$.order.id", que captura do payload publicado
+type Capture struct {
+	// From é a origem do valor: payload (padrão), headers ou reply
+	From string `json:"from,omitempty"`
+	// Path é o caminho JSONPath do valor na origem
+	Path string `json:"path"`
+}
+
+// UnmarshalJSON aceita a forma curta (apenas o caminho) e a forma completa
+func (c *Capture) UnmarshalJSON(data []byte) error {
+	var path string
+	if err := json.Unmarshal(data, &path); err == nil {
+		*c = Capture{Path: path}
+		return nil
+	}
+
+	type plain Capture
+
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+// Source retorna a origem da captura, com payload como padrão
+func (c Capture) Source() string {
+	if c.From == "" {
+		return CapturePayload
+	}
+
+	return strings.ToLower(c.From)
+}
+
+// Validate verifica a origem e o caminho da captura
+func (c Capture) Validate() error {
+	switch c.Source() {
+	case CapturePayload, CaptureHeaders, CaptureReply:
+	default:
+		return fmt.Errorf("origem '%s' inválida (use %s, %s ou %s)", c.From, CapturePayload, CaptureHeaders, CaptureReply)
+	}
+
+	if strings.TrimSpace(c.Path) == "" {
+		return errors.New("o campo 'path' é obrigatório")
+	}
+
+	_, err := jpath.Parse(c.Path)
+
+	return err
+}
+
+// ValidateCaptures verifica um conjunto de regras indexadas pelo nome da variável
+func ValidateCaptures(rules map[string]Capture) error {
+	for _, name := range CaptureNames(rules) {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("nome de variável vazio")
+		}
+
+		if err := rules[name].Validate(); err != nil {
+			return fmt.Errorf("'%s': %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// CaptureNames retorna os nomes das variáveis em ordem alfabética
+func CaptureNames(rules map[string]Capture) []string {
+	names := make([]string, 0, len(rules))
+	for name := range rules {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// UsesReply indica se alguma regra captura da resposta do modo request/reply
+func UsesReply(rules map[string]Capture) bool {
+	for _, rule := range rules {
+		if rule.Source() == CaptureReply {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package rabbix
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCaptureFromTestCaseFile(t *testing.T) {
+	// A forma curta captura do payload; a completa escolhe a origem
+	data := `{
+		"name": "consulta",
+		"route_key": "precos",
+		"capture": {
+			"pedido": "$.order.id",
+			"tenant": {"from": "headers", "path": "$.tenant"},
+			"status": {"from": "REPLY", "path": "$.status"}
+		}
+	}`
+
+	var tc TestCase
+	if err := json.Unmarshal([]byte(data), &tc); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tc.Validate(); err != nil {
+		t.Fatalf("Validate erro inesperado: %v", err)
+	}
+
+	sources := map[string]string{}
+	for name, rule := range tc.Capture {
+		sources[name] = rule.Source() + " " + rule.Path
+	}
+
+	want := map[string]string{
+		"pedido": "payload $.order.id",
+		"tenant": "headers $.tenant",
+		"status": "reply $.status",
+	}
+
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("capture = %v, esperado %v", sources, want)
+	}
+
+	if names := CaptureNames(tc.Capture); !reflect.DeepEqual(names, []string{"pedido", "status", "tenant"}) {
+		t.Errorf("CaptureNames = %v, esperado em ordem alfabética", names)
+	}
+
+	// A captura da resposta exige o modo request/reply
+	if !UsesReply(tc.Capture) {
+		t.Error("UsesReply deveria indicar a captura from: reply")
+	}
+
+	delete(tc.Capture, "status")
+
+	if UsesReply(tc.Capture) {
+		t.Error("UsesReply sem captura da resposta")
+	}
+}
+
+func TestTestCaseValidateCapture(t *testing.T) {
+	tests := []struct {
+		name    string
+		capture map[string]Capture
+		wantErr string
+	}{
+		{name: "origem inválida", capture: map[string]Capture{"id": {From: "body", Path: "$.id"}}, wantErr: "origem 'body'"},
+		{name: "sem caminho", capture: map[string]Capture{"id": {}}, wantErr: "'path' é obrigatório"},
+		{name: "caminho inválido", capture: map[string]Capture{"id": {Path: "$.itens["}}, wantErr: "'id'"},
+		{name: "sem nome", capture: map[string]Capture{" ": {Path: "$.id"}}, wantErr: "nome de variável vazio"},
+	}
+
+	for _, tt := range tests {
+		tc := TestCase{Name: "pedido", RouteKey: "pedidos", Capture: tt.capture}
+
+		if err := tc.Validate(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Validate erro = %v, esperado contendo %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
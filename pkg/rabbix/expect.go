@@ -0,0 +1,77 @@
+package rabbix
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/jpath"
+)
+
+// DefaultExpectTimeout é o tempo de espera quando o bloco expect não define um
+const DefaultExpectTimeout = 5 * time.Second
+
+// Expect descreve o resultado esperado após a publicação: as mensagens que
+// devem chegar em uma fila e asserções sobre payload e headers
+type Expect struct {
+	// Queue é a fila observada; as mensagens lidas são consumidas
+	Queue string `json:"queue"`
+	// Vhost da fila observada (padrão: o da publicação)
+	Vhost string `json:"vhost,omitempty"`
+	// Purge esvazia a fila observada antes da publicação, descartando mensagens
+	// antigas. Use apenas em filas exclusivas do teste
+	Purge bool `json:"purge,omitempty"`
+	// Timeout no formato de duração do Go, ex: "5s", "500ms"
+	Timeout string `json:"timeout,omitempty"`
+	// Count é a quantidade exata de mensagens esperadas; sem ele, basta uma
+	Count *int `json:"count,omitempty"`
+	// Payload mapeia caminhos JSONPath ("$.order.id") para o valor esperado
+	Payload map[string]any `json:"payload,omitempty"`
+	// Headers mapeia caminhos dos headers para o valor esperado
+	Headers map[string]any `json:"headers,omitempty"`
+	// Equals compara o payload inteiro com o JSON informado
+	Equals any `json:"equals,omitempty"`
+}
+
+// Validate verifica se o bloco expect pode ser avaliado
+func (e Expect) Validate() error {
+	if e.Queue == "" {
+		return errors.New("o campo 'queue' é obrigatório")
+	}
+
+	if _, err := e.TimeoutDuration(); err != nil {
+		return err
+	}
+
+	if e.Count != nil && *e.Count < 0 {
+		return fmt.Errorf("count deve ser maior ou igual a zero, recebido %d", *e.Count)
+	}
+
+	for path := range e.Payload {
+		if _, err := jpath.Parse(path); err != nil {
+			return fmt.Errorf("payload: %w", err)
+		}
+	}
+
+	for path := range e.Headers {
+		if _, err := jpath.Parse(path); err != nil {
+			return fmt.Errorf("headers: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// TimeoutDuration interpreta o timeout, aplicando o padrão quando vazio
+func (e Expect) TimeoutDuration() (time.Duration, error) {
+	if e.Timeout == "" {
+		return DefaultExpectTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(e.Timeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("timeout inválido '%s' (ex: 5s, 500ms)", e.Timeout)
+	}
+
+	return timeout, nil
+}
@@ -0,0 +1,63 @@
+package rabbix
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpectFromTestCaseFile(t *testing.T) {
+	data := `{
+		"name": "pedido-criado",
+		"route_key": "pedidos",
+		"expect": {"queue": "pedidos.criados", "purge": true, "count": 0, "payload": {"$.order.id": 1}}
+	}`
+
+	var tc TestCase
+	if err := json.Unmarshal([]byte(data), &tc); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tc.Validate(); err != nil {
+		t.Fatalf("Validate erro inesperado: %v", err)
+	}
+
+	// count 0 é uma asserção válida: nenhuma mensagem deve chegar
+	if !tc.Expect.Purge || tc.Expect.Count == nil || *tc.Expect.Count != 0 {
+		t.Errorf("expect = %+v, esperado purge e count 0", tc.Expect)
+	}
+
+	if timeout, _ := tc.Expect.TimeoutDuration(); timeout != DefaultExpectTimeout {
+		t.Errorf("TimeoutDuration = %v, esperado o padrão %v", timeout, DefaultExpectTimeout)
+	}
+
+	tc.Expect.Timeout = "750ms"
+	if timeout, _ := tc.Expect.TimeoutDuration(); timeout != 750*time.Millisecond {
+		t.Errorf("TimeoutDuration = %v, esperado 750ms", timeout)
+	}
+}
+
+func TestTestCaseValidateExpect(t *testing.T) {
+	negative := -1
+
+	tests := []struct {
+		name    string
+		expect  Expect
+		wantErr string
+	}{
+		{name: "sem fila", expect: Expect{}, wantErr: "o campo 'queue' é obrigatório"},
+		{name: "timeout inválido", expect: Expect{Queue: "q", Timeout: "0s"}, wantErr: "timeout inválido"},
+		{name: "count negativo", expect: Expect{Queue: "q", Count: &negative}, wantErr: "count deve ser maior"},
+		{name: "caminho inválido", expect: Expect{Queue: "q", Headers: map[string]any{"a..b": 1}},
+			wantErr: "headers: caminho inválido"},
+	}
+
+	for _, tt := range tests {
+		tc := TestCase{Name: "pedido", RouteKey: "pedidos", Expect: &tt.expect}
+
+		if err := tc.Validate(); err == nil || !strings.Contains(err.Error(), "expect inválido: "+tt.wantErr) {
+			t.Errorf("%s: Validate erro = %v, esperado contendo %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
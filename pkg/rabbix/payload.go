@@ -0,0 +1,160 @@
+package rabbix
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Payload descreve um corpo de mensagem que não é um objeto JSON. Apenas uma
+// das fontes deve ser informada; sem payload, o corpo é o 'json_pool'
+type Payload struct {
+	// Text é enviado como está (texto puro, XML, CSV...)
+	Text string `json:"text,omitempty"`
+	// JSON aceita qualquer valor JSON, inclusive arrays e escalares
+	JSON json.RawMessage `json:"json,omitempty"`
+	// Base64 é decodificado e enviado como bytes (protobuf, Avro...)
+	Base64 string `json:"base64,omitempty"`
+	// File é lido no momento da publicação; caminhos relativos partem do output_dir
+	File string `json:"file,omitempty"`
+}
+
+// Validate verifica se exatamente uma fonte de payload foi informada
+func (p Payload) Validate() error {
+	sources := 0
+
+	for _, set := range []bool{p.Text != "", len(p.JSON) > 0, p.Base64 != "", p.File != ""} {
+		if set {
+			sources++
+		}
+	}
+
+	if sources != 1 {
+		return errors.New("informe exatamente um entre 'text', 'json', 'base64' ou 'file'")
+	}
+
+	if len(p.JSON) > 0 && !json.Valid(p.JSON) {
+		return errors.New("'json' não contém um JSON válido")
+	}
+
+	if p.Base64 != "" {
+		if _, err := base64.StdEncoding.DecodeString(p.Base64); err != nil {
+			return fmt.Errorf("'base64' inválido: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DetectPayload guarda objetos JSON no json_pool e os demais formatos em
+// payload: outros valores JSON, texto puro ou bytes em base64. Objetos com
+// inteiros que o json_pool arredondaria, como IDs de 64 bits, vão para
+// payload.json, que é publicado byte a byte
+func DetectPayload(raw []byte) (map[string]any, *Payload) {
+	var pool map[string]any
+	if err := json.Unmarshal(raw, &pool); err == nil && pool != nil && !hasLargeIntegers(raw) {
+		return pool, nil
+	}
+
+	if json.Valid(raw) {
+		return nil, &Payload{JSON: json.RawMessage(bytes.TrimSpace(raw))}
+	}
+
+	if utf8.Valid(raw) {
+		return nil, &Payload{Text: string(raw)}
+	}
+
+	return nil, &Payload{Base64: base64.StdEncoding.EncodeToString(raw)}
+}
+
+// hasLargeIntegers indica se o JSON tem inteiros fora da faixa exata do float64 (±2^53)
+func hasLargeIntegers(raw []byte) bool {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value any
+	if decoder.Decode(&value) != nil {
+		return false
+	}
+
+	return largeInteger(value)
+}
+
+func largeInteger(value any) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, item := range v {
+			if largeInteger(item) {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if largeInteger(item) {
+				return true
+			}
+		}
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			return false
+		}
+
+		n, err := strconv.ParseInt(v.String(), 10, 64)
+
+		return err != nil || n > 1<<53 || n < -(1<<53)
+	}
+
+	return false
+}
+
+// Body retorna o corpo da mensagem e se ele é binário, ou seja, se precisa
+// ser enviado em base64 pela API HTTP de gerenciamento
+func (tc TestCase) Body(baseDir string) ([]byte, bool, error) {
+	if tc.Payload == nil {
+		body, err := json.Marshal(tc.JSONPool)
+		if err != nil {
+			return nil, false, fmt.Errorf("erro ao serializar payload: %w", err)
+		}
+
+		return body, false, nil
+	}
+
+	if err := tc.Payload.Validate(); err != nil {
+		return nil, false, fmt.Errorf("payload inválido: %w", err)
+	}
+
+	switch {
+	case tc.Payload.Text != "":
+		return []byte(tc.Payload.Text), false, nil
+	case len(tc.Payload.JSON) > 0:
+		// Remove a indentação herdada do arquivo do caso de teste
+		var body bytes.Buffer
+		if err := json.Compact(&body, tc.Payload.JSON); err != nil {
+			return nil, false, fmt.Errorf("payload inválido: %w", err)
+		}
+
+		return body.Bytes(), false, nil
+	case tc.Payload.Base64 != "":
+		body, _ := base64.StdEncoding.DecodeString(tc.Payload.Base64)
+		return body, true, nil
+	default:
+		path := tc.Payload.File
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("erro ao ler payload de '%s': %w", path, err)
+		}
+
+		return body, !utf8.Valid(body), nil
+	}
+}
@@ -0,0 +1,110 @@
+package rabbix
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectPayloadRoundTrip(t *testing.T) {
+	// O corpo gravado com DetectPayload precisa ser publicado com os mesmos bytes
+	tests := []struct {
+		name       string
+		raw        []byte
+		wantPool   bool
+		wantBinary bool
+	}{
+		{name: "objeto", raw: []byte(`{"id":1,"status":"criado"}`), wantPool: true},
+		{name: "id de 64 bits", raw: []byte(`{"id":12345678901234567891,"itens":[9007199254740993]}`)},
+		{name: "array", raw: []byte(`[1,2,3]`)},
+		{name: "texto", raw: []byte("<pedido id=\"1\"/>")},
+		{name: "binário", raw: []byte{0x0a, 0xff, 0x00, 0x12}, wantBinary: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool, payload := DetectPayload(tt.raw)
+			if (pool != nil) != tt.wantPool || (payload != nil) == tt.wantPool {
+				t.Fatalf("DetectPayload = json_pool %v, payload %+v", pool, payload)
+			}
+
+			tc := TestCase{Name: "gravado", RouteKey: "pedidos", JSONPool: pool, Payload: payload}
+			if err := tc.Validate(); err != nil {
+				t.Fatalf("caso gravado inválido: %v", err)
+			}
+
+			body, binary, err := tc.Body("")
+			if err != nil {
+				t.Fatalf("Body: %v", err)
+			}
+
+			if !bytes.Equal(body, tt.raw) || binary != tt.wantBinary {
+				t.Errorf("Body = %q (binário %v), esperado %q (binário %v)", body, binary, tt.raw, tt.wantBinary)
+			}
+		})
+	}
+}
+
+func TestBody(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pedido.xml"), []byte("<pedido/>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "pedido.bin"), []byte{0xff, 0xfe}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		payload    *Payload
+		want       string
+		wantBinary bool
+	}{
+		{name: "json indentado é compactado", payload: &Payload{JSON: json.RawMessage("[\n  1,\n  2\n]")}, want: "[1,2]"},
+		{name: "arquivo relativo ao output_dir", payload: &Payload{File: "pedido.xml"}, want: "<pedido/>"},
+		{
+			name:       "arquivo binário",
+			payload:    &Payload{File: filepath.Join(dir, "pedido.bin")},
+			want:       "\xff\xfe",
+			wantBinary: true,
+		},
+	}
+
+	for _, tt := range tests {
+		body, binary, err := TestCase{Payload: tt.payload}.Body(dir)
+		if err != nil || string(body) != tt.want || binary != tt.wantBinary {
+			t.Errorf("%s: Body = %q, binário %v, erro %v", tt.name, body, binary, err)
+		}
+	}
+
+	if _, _, err := (TestCase{Payload: &Payload{File: "ausente.bin"}}).Body(dir); err == nil {
+		t.Error("Body deveria falhar com arquivo inexistente")
+	}
+}
+
+func TestTestCaseValidatePayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		tc      TestCase
+		wantErr string
+	}{
+		{name: "json_pool e payload", tc: TestCase{JSONPool: map[string]any{"id": 1}, Payload: &Payload{Text: "x"}},
+			wantErr: "não os dois"},
+		{name: "sem fonte", tc: TestCase{Payload: &Payload{}}, wantErr: "exatamente um"},
+		{name: "duas fontes", tc: TestCase{Payload: &Payload{Text: "x", File: "a.bin"}}, wantErr: "exatamente um"},
+		{name: "json inválido", tc: TestCase{Payload: &Payload{JSON: json.RawMessage("{")}}, wantErr: "JSON válido"},
+		{name: "base64 inválido", tc: TestCase{Payload: &Payload{Base64: "%%"}}, wantErr: "'base64' inválido"},
+	}
+
+	for _, tt := range tests {
+		tt.tc.Name, tt.tc.RouteKey = "pedido", "pedidos"
+
+		if err := tt.tc.Validate(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Validate erro = %v, esperado contendo %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package rabbix
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Properties são as propriedades AMQP básicas enviadas junto com a mensagem.
+// Os headers continuam no campo 'headers' do caso de teste
+type Properties struct {
+	ContentType   string `json:"content_type,omitempty"`
+	DeliveryMode  uint8  `json:"delivery_mode,omitempty"`
+	Priority      uint8  `json:"priority,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	MessageID     string `json:"message_id,omitempty"`
+	ReplyTo       string `json:"reply_to,omitempty"`
+	Expiration    string `json:"expiration,omitempty"`
+	Type          string `json:"type,omitempty"`
+	AppID         string `json:"app_id,omitempty"`
+	Timestamp     int64  `json:"timestamp,omitempty"`
+	UserID        string `json:"user_id,omitempty"`
+}
+
+// Validate verifica se os valores das propriedades são aceitos pelo RabbitMQ
+func (p Properties) Validate() error {
+	if p.DeliveryMode > 2 {
+		return fmt.Errorf("delivery_mode deve ser 1 (transiente) ou 2 (persistente), recebido %d", p.DeliveryMode)
+	}
+
+	if p.Expiration != "" {
+		if _, err := strconv.ParseUint(p.Expiration, 10, 32); err != nil {
+			return fmt.Errorf("expiration deve ser um TTL em milissegundos, recebido '%s'", p.Expiration)
+		}
+	}
+
+	if p.Timestamp < 0 {
+		return fmt.Errorf("timestamp deve ser um Unix timestamp em segundos, recebido %d", p.Timestamp)
+	}
+
+	return nil
+}
+
+// Set atribui uma propriedade pelo nome usado no JSON do caso de teste
+func (p *Properties) Set(key, value string) error {
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "content_type":
+		p.ContentType = value
+	case "delivery_mode":
+		mode, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			return fmt.Errorf("delivery_mode inválido '%s'", value)
+		}
+
+		p.DeliveryMode = uint8(mode)
+	case "priority":
+		priority, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			return fmt.Errorf("priority deve estar entre 0 e 255, recebido '%s'", value)
+		}
+
+		p.Priority = uint8(priority)
+	case "correlation_id":
+		p.CorrelationID = value
+	case "message_id":
+		p.MessageID = value
+	case "reply_to":
+		p.ReplyTo = value
+	case "expiration":
+		p.Expiration = value
+	case "type":
+		p.Type = value
+	case "app_id":
+		p.AppID = value
+	case "timestamp":
+		timestamp, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("timestamp inválido '%s'", value)
+		}
+
+		p.Timestamp = timestamp
+	case "user_id":
+		p.UserID = value
+	default:
+		return fmt.Errorf("propriedade desconhecida '%s'", key)
+	}
+
+	return p.Validate()
+}
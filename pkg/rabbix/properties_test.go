@@ -0,0 +1,73 @@
+package rabbix
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPropertiesSet(t *testing.T) {
+	// Cada par do -P chega como texto e é convertido para o tipo da propriedade
+	var properties Properties
+
+	pairs := [][2]string{
+		{"content_type", "application/json"},
+		{"DELIVERY_MODE", "2"},
+		{"priority", "9"},
+		{" correlation_id ", "pedido-1"},
+		{"expiration", "60000"},
+		{"timestamp", "1700000000"},
+	}
+
+	for _, pair := range pairs {
+		if err := properties.Set(pair[0], pair[1]); err != nil {
+			t.Fatalf("Set(%q, %q): %v", pair[0], pair[1], err)
+		}
+	}
+
+	want := Properties{
+		ContentType:   "application/json",
+		DeliveryMode:  2,
+		Priority:      9,
+		CorrelationID: "pedido-1",
+		Expiration:    "60000",
+		Timestamp:     1700000000,
+	}
+
+	if properties != want {
+		t.Errorf("Set = %+v, esperado %+v", properties, want)
+	}
+}
+
+func TestPropertiesSetErrors(t *testing.T) {
+	tests := []struct {
+		key, value string
+		wantErr    string
+	}{
+		{key: "delivery_mode", value: "3", wantErr: "delivery_mode deve ser 1"},
+		{key: "delivery_mode", value: "persistente", wantErr: "delivery_mode inválido"},
+		{key: "priority", value: "256", wantErr: "priority deve estar entre 0 e 255"},
+		{key: "expiration", value: "1m", wantErr: "TTL em milissegundos"},
+		{key: "timestamp", value: "-1", wantErr: "Unix timestamp"},
+		{key: "headers", value: "x", wantErr: "propriedade desconhecida"},
+	}
+
+	for _, tt := range tests {
+		var properties Properties
+
+		if err := properties.Set(tt.key, tt.value); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Set(%q, %q) erro = %v, esperado contendo %q", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTestCaseValidateProperties(t *testing.T) {
+	tc := TestCase{Name: "pedido", RouteKey: "pedidos", Properties: &Properties{DeliveryMode: 2, Expiration: "60000"}}
+	if err := tc.Validate(); err != nil {
+		t.Fatalf("Validate erro inesperado: %v", err)
+	}
+
+	tc.Properties.Expiration = "1m"
+	if err := tc.Validate(); err == nil || !strings.Contains(err.Error(), "properties inválidas: expiration") {
+		t.Errorf("Validate erro = %v, esperado properties inválidas", err)
+	}
+}
@@ -1,8 +1,69 @@
 package rabbix
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultExchange é o exchange padrão do RabbitMQ, que roteia pelo nome da fila
+const DefaultExchange = "amq.default"
+
+// DefaultVhost é o virtual host usado quando nenhum outro é configurado
+const DefaultVhost = "/"
+
 type TestCase struct {
-	Name     string         `json:"name"`
-	RouteKey string         `json:"route_key"`
-	JSONPool map[string]any `json:"json_pool"`
-	Headers  map[string]any `json:"headers"`
+	Name       string         `json:"name"`
+	RouteKey   string         `json:"route_key"`
+	Exchange   string         `json:"exchange,omitempty"`
+	Vhost      string         `json:"vhost,omitempty"`
+	Transport  string         `json:"transport,omitempty"`
+	JSONPool   map[string]any `json:"json_pool"`
+	Headers    map[string]any `json:"headers"`
+	Properties *Properties    `json:"properties,omitempty"`
+	Payload    *Payload       `json:"payload,omitempty"`
+	Expect     *Expect        `json:"expect,omitempty"`
+	// Capture guarda valores da mensagem em variáveis para as mensagens seguintes
+	Capture map[string]Capture `json:"capture,omitempty"`
+}
+
+// Validate verifica se o caso de teste possui os campos obrigatórios
+func (tc TestCase) Validate() error {
+	if strings.TrimSpace(tc.Name) == "" {
+		return errors.New("o campo 'name' é obrigatório")
+	}
+
+	// No exchange padrão a routing key é o nome da fila, então é obrigatória
+	usesDefaultExchange := tc.Exchange == "" || tc.Exchange == DefaultExchange
+	if usesDefaultExchange && strings.TrimSpace(tc.RouteKey) == "" {
+		return errors.New("o campo 'route_key' é obrigatório no exchange padrão")
+	}
+
+	if tc.Payload != nil {
+		if len(tc.JSONPool) > 0 {
+			return errors.New("use 'json_pool' ou 'payload', não os dois")
+		}
+
+		if err := tc.Payload.Validate(); err != nil {
+			return fmt.Errorf("payload inválido: %w", err)
+		}
+	}
+
+	if tc.Properties != nil {
+		if err := tc.Properties.Validate(); err != nil {
+			return fmt.Errorf("properties inválidas: %w", err)
+		}
+	}
+
+	if tc.Expect != nil {
+		if err := tc.Expect.Validate(); err != nil {
+			return fmt.Errorf("expect inválido: %w", err)
+		}
+	}
+
+	if err := ValidateCaptures(tc.Capture); err != nil {
+		return fmt.Errorf("capture inválido: %w", err)
+	}
+
+	return nil
 }
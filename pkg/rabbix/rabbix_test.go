@@ -0,0 +1,44 @@
+package rabbix
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTestCaseValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		tc      TestCase
+		wantErr string
+	}{
+		{name: "mínimo", tc: TestCase{Name: "pedido", RouteKey: "pedidos"}},
+		{
+			name: "exchange nomeado sem route key",
+			tc:   TestCase{Name: "pedido", Exchange: "amq.fanout"},
+		},
+		{name: "sem nome", tc: TestCase{Name: "  ", RouteKey: "pedidos"}, wantErr: "'name' é obrigatório"},
+		{name: "sem route key", tc: TestCase{Name: "pedido"}, wantErr: "'route_key' é obrigatório"},
+		{
+			name:    "sem route key no amq.default",
+			tc:      TestCase{Name: "pedido", Exchange: DefaultExchange, RouteKey: " "},
+			wantErr: "'route_key' é obrigatório",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tc.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate erro inesperado: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate erro = %v, esperado contendo %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package rabbix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/jpath"
+)
+
+// DefaultReplyTimeout é a espera pela resposta de um passo RPC sem reply_timeout
+const DefaultReplyTimeout = 10 * time.Second
+
+// Scenario é um fluxo de passos executados em ordem, como "pedido criado →
+// pagamento aprovado → pedido enviado"
+type Scenario struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Vars são os valores iniciais disponíveis nos placeholders como {{vars.nome}}
+	Vars map[string]any `json:"vars,omitempty"`
+	// ContinueOnError segue para os próximos passos após uma falha; apenas os
+	// passos que dependem do passo com falha são pulados
+	ContinueOnError bool   `json:"continue_on_error,omitempty"`
+	Steps           []Step `json:"steps"`
+}
+
+// Step é um passo do cenário. Ele pode publicar um caso de teste salvo,
+// aguardar um intervalo, observar uma fila ou combinar essas ações, nessa
+// ordem: espera, publicação, captura e expect
+type Step struct {
+	// Name identifica o passo no resultado e em depends_on (padrão: o nome do teste)
+	Name string `json:"name,omitempty"`
+	// Test é o caso de teste salvo a ser publicado
+	Test string `json:"test,omitempty"`
+	// Wait é a pausa antes do passo, no formato de duração do Go, ex: "500ms"
+	Wait string `json:"wait,omitempty"`
+	// RouteKey sobrescreve a route key do teste
+	RouteKey string `json:"route_key,omitempty"`
+	// Set sobrescreve valores do json_pool do teste por caminho, ex: {"order.id": "{{vars.orderId}}"}
+	Set map[string]any `json:"set,omitempty"`
+	// Headers são mesclados aos headers do teste
+	Headers map[string]any `json:"headers,omitempty"`
+	// Capture guarda valores da mensagem em variáveis, ex: {"orderId": "$.order.id"}.
+	// As regras se somam às do teste, com precedência para as do passo
+	Capture map[string]Capture `json:"capture,omitempty"`
+	// RPC publica com reply_to e aguarda a resposta, que pode ser capturada com from: reply
+	RPC bool `json:"rpc,omitempty"`
+	// ReplyTimeout é a espera pela resposta no modo RPC (padrão: 10s)
+	ReplyTimeout string `json:"reply_timeout,omitempty"`
+	// Expect observa uma fila após a publicação, substituindo o expect do teste.
+	// Sem teste, o passo apenas verifica a fila
+	Expect *Expect `json:"expect,omitempty"`
+	// DependsOn lista os passos anteriores que precisam ter sucesso para este executar
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// StepName retorna o nome do passo, com o teste ou a posição como padrão
+func (s Step) StepName(index int) string {
+	switch {
+	case s.Name != "":
+		return s.Name
+	case s.Test != "":
+		return s.Test
+	default:
+		return fmt.Sprintf("passo-%d", index+1)
+	}
+}
+
+// WaitDuration interpreta a pausa do passo; vazia significa sem pausa
+func (s Step) WaitDuration() (time.Duration, error) {
+	if s.Wait == "" {
+		return 0, nil
+	}
+
+	wait, err := time.ParseDuration(s.Wait)
+	if err != nil || wait < 0 {
+		return 0, fmt.Errorf("wait inválido '%s' (ex: 500ms, 2s)", s.Wait)
+	}
+
+	return wait, nil
+}
+
+// ReplyTimeoutDuration interpreta a espera pela resposta no modo RPC
+func (s Step) ReplyTimeoutDuration() (time.Duration, error) {
+	if s.ReplyTimeout == "" {
+		return DefaultReplyTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(s.ReplyTimeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("reply_timeout inválido '%s' (ex: 5s, 500ms)", s.ReplyTimeout)
+	}
+
+	return timeout, nil
+}
+
+// Validate verifica os passos, os nomes e as dependências do cenário
+func (s Scenario) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("o campo 'name' é obrigatório")
+	}
+
+	if len(s.Steps) == 0 {
+		return errors.New("o cenário não possui passos em 'steps'")
+	}
+
+	seen := map[string]bool{}
+
+	for i, step := range s.Steps {
+		name := step.StepName(i)
+
+		if err := step.validate(seen); err != nil {
+			return fmt.Errorf("passo %d (%s): %w", i+1, name, err)
+		}
+
+		if seen[name] {
+			return fmt.Errorf("passo %d: nome '%s' repetido, defina 'name' para diferenciar os passos", i+1, name)
+		}
+
+		seen[name] = true
+	}
+
+	return nil
+}
+
+// validate verifica um passo; previous são os nomes dos passos anteriores
+func (s Step) validate(previous map[string]bool) error {
+	if s.Test == "" && s.Wait == "" && s.Expect == nil {
+		return errors.New("informe ao menos um entre 'test', 'wait' ou 'expect'")
+	}
+
+	if _, err := s.WaitDuration(); err != nil {
+		return err
+	}
+
+	if s.Test == "" && (s.RouteKey != "" || len(s.Set) > 0 || len(s.Headers) > 0 || len(s.Capture) > 0 || s.RPC) {
+		return errors.New("'route_key', 'set', 'headers', 'capture' e 'rpc' exigem um 'test'")
+	}
+
+	if _, err := s.ReplyTimeoutDuration(); err != nil {
+		return err
+	}
+
+	for path := range s.Set {
+		if _, err := jpath.Parse(path); err != nil {
+			return fmt.Errorf("set: %w", err)
+		}
+	}
+
+	if err := ValidateCaptures(s.Capture); err != nil {
+		return fmt.Errorf("capture: %w", err)
+	}
+
+	if UsesReply(s.Capture) && !s.RPC {
+		return errors.New("capture com from: reply exige 'rpc: true'")
+	}
+
+	if s.Expect != nil {
+		if err := s.Expect.Validate(); err != nil {
+			return fmt.Errorf("expect inválido: %w", err)
+		}
+	}
+
+	for _, dependency := range s.DependsOn {
+		if !previous[dependency] {
+			return fmt.Errorf("depends_on: passo '%s' não encontrado entre os passos anteriores", dependency)
+		}
+	}
+
+	return nil
+}
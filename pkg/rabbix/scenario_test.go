@@ -0,0 +1,135 @@
+package rabbix
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScenarioValidate(t *testing.T) {
+	scenario := func(steps ...Step) Scenario {
+		return Scenario{Name: "fluxo-pedido", Steps: steps}
+	}
+
+	tests := []struct {
+		name     string
+		scenario Scenario
+		wantErr  string
+	}{
+		{
+			name: "fluxo com dependências",
+			scenario: scenario(
+				Step{Test: "pedido-criado", Capture: map[string]Capture{"id": {Path: "$.id"}}},
+				Step{Wait: "500ms"},
+				Step{
+					Name: "pagamento", Test: "pagamento-aprovado", DependsOn: []string{"pedido-criado"},
+					Set: map[string]any{"order.id": "{{vars.id}}"},
+				},
+				Step{Expect: &Expect{Queue: "pedidos.enviados"}, DependsOn: []string{"pagamento"}},
+			),
+		},
+		{
+			name: "mesmo teste com nomes diferentes",
+			scenario: scenario(
+				Step{Name: "primeiro", Test: "pedido-criado"},
+				Step{Name: "segundo", Test: "pedido-criado"},
+			),
+		},
+		{
+			name: "rpc com captura da resposta",
+			scenario: scenario(Step{
+				Test: "consulta", RPC: true, ReplyTimeout: "2s",
+				Capture: map[string]Capture{"status": {From: "reply", Path: "$.status"}},
+			}),
+		},
+		{name: "sem nome", scenario: Scenario{Steps: []Step{{Test: "a"}}}, wantErr: "'name' é obrigatório"},
+		{name: "sem passos", scenario: scenario(), wantErr: "não possui passos"},
+		{name: "passo vazio", scenario: scenario(Step{Name: "nada"}), wantErr: "passo 1 (nada): informe ao menos um"},
+		{
+			name:     "nome repetido",
+			scenario: scenario(Step{Test: "pedido-criado"}, Step{Test: "pedido-criado"}),
+			wantErr:  "passo 2: nome 'pedido-criado' repetido",
+		},
+		{
+			name:     "nome padrão repetido",
+			scenario: scenario(Step{Wait: "1s"}, Step{Name: "passo-1", Wait: "1s"}),
+			wantErr:  "nome 'passo-1' repetido",
+		},
+		{name: "wait inválido", scenario: scenario(Step{Wait: "1 minuto"}), wantErr: "wait inválido"},
+		{name: "wait negativo", scenario: scenario(Step{Wait: "-1s"}), wantErr: "wait inválido"},
+		{
+			name:     "set sem teste",
+			scenario: scenario(Step{Wait: "1s", Set: map[string]any{"id": 1}}),
+			wantErr:  "exigem um 'test'",
+		},
+		{
+			name:     "rpc sem teste",
+			scenario: scenario(Step{Expect: &Expect{Queue: "q"}, RPC: true}),
+			wantErr:  "exigem um 'test'",
+		},
+		{
+			name:     "reply timeout inválido",
+			scenario: scenario(Step{Test: "consulta", RPC: true, ReplyTimeout: "0s"}),
+			wantErr:  "reply_timeout inválido",
+		},
+		{
+			name:     "set com caminho inválido",
+			scenario: scenario(Step{Test: "pedido", Set: map[string]any{"itens[": 1}}),
+			wantErr:  "set: caminho inválido",
+		},
+		{
+			name:     "captura da resposta sem rpc",
+			scenario: scenario(Step{Test: "pedido", Capture: map[string]Capture{"id": {From: "reply", Path: "$.id"}}}),
+			wantErr:  "exige 'rpc: true'",
+		},
+		{
+			name:     "expect inválido",
+			scenario: scenario(Step{Test: "pedido", Expect: &Expect{}}),
+			wantErr:  "expect inválido",
+		},
+		{
+			name:     "dependência posterior",
+			scenario: scenario(Step{Test: "a", DependsOn: []string{"b"}}, Step{Test: "b"}),
+			wantErr:  "depends_on: passo 'b' não encontrado",
+		},
+		{
+			name:     "dependência de si mesmo",
+			scenario: scenario(Step{Test: "a", DependsOn: []string{"a"}}),
+			wantErr:  "depends_on: passo 'a' não encontrado",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.scenario.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate erro inesperado: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate erro = %v, esperado contendo %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStepName(t *testing.T) {
+	tests := []struct {
+		step  Step
+		index int
+		want  string
+	}{
+		{step: Step{Name: "pagamento", Test: "pagamento-aprovado"}, want: "pagamento"},
+		{step: Step{Test: "pedido-criado"}, want: "pedido-criado"},
+		{step: Step{Wait: "1s"}, index: 2, want: "passo-3"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.step.StepName(tt.index); got != tt.want {
+			t.Errorf("StepName(%d) = %q, esperado %q", tt.index, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package record
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/cache"
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	"github.com/maxwelbm/rabbix/pkg/request"
+	"github.com/maxwelbm/rabbix/pkg/sett"
+	"github.com/spf13/cobra"
+)
+
+// discardedProperties não são gravadas: o broker recusa um user_id diferente do
+// usuário da publicação, e timestamp e message_id identificam a mensagem original
+var discardedProperties = map[string]bool{"headers": true, "user_id": true, "timestamp": true, "message_id": true}
+
+type Record struct {
+	settings sett.SettItf
+	Cache    cache.CacheItf
+	request  request.RequestItf
+}
+
+func New(
+	settings sett.SettItf,
+	cache cache.CacheItf,
+	request request.RequestItf,
+) *Record {
+	return &Record{
+		settings: settings,
+		Cache:    cache,
+		request:  request,
+	}
+}
+
+func (r *Record) CmdRecord() *cobra.Command {
+	var (
+		options request.GetOptions
+		prefix  string
+	)
+
+	var cmd = &cobra.Command{
+		Use:   "record [queue]",
+		Short: "Grava mensagens de uma fila como casos de teste",
+		Long: `Lê mensagens de uma fila e salva cada uma como um caso de teste no diretório
+de saída, com routing key, headers, properties e payload, para reexecução com run e batch.
+As properties user_id, timestamp e message_id não são gravadas. Corpos com {{ são
+gravados em payload, que a reexecução publica sem avaliar placeholders.
+Por padrão as mensagens são recolocadas na fila após a leitura.
+Exemplos:
+  rabbix record pedidos
+  rabbix record pedidos --count 20 --prefix pedido-real`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.Queue = args[0]
+			if prefix == "" {
+				prefix = options.Queue
+			}
+
+			if strings.ContainsAny(prefix, `/\`) {
+				return exitcode.New(exitcode.Config,
+					"prefixo inválido '%s': não pode conter separadores de diretório", prefix)
+			}
+
+			// Carrega configuração para obter diretório de saída
+			settings := r.settings.LoadSettings()
+			outputDir := settings["output_dir"]
+			if outputDir == "" {
+				home, _ := os.UserHomeDir()
+				outputDir = filepath.Join(home, ".rabbix", "tests")
+			}
+
+			output.Printf("🎙️  Gravando até %d mensagem(ns) da fila '%s'\n", options.Count, options.Queue)
+			output.Println("─────────────────────────────────────")
+
+			defer func() {
+				if err := r.request.Close(); err != nil {
+					output.Printf("❌ Erro ao encerrar conexão: %v\n", err)
+				}
+			}()
+
+			messages, err := r.request.Get(cmd.Context(), options)
+			if err != nil {
+				if request.IsConfigError(err) {
+					return exitcode.Wrap(exitcode.Config, err)
+				}
+
+				return err
+			}
+
+			if len(messages) == 0 {
+				output.Println("📭 Nenhuma mensagem na fila")
+				return nil
+			}
+
+			_ = os.MkdirAll(outputDir, os.ModePerm)
+
+			stamp := time.Now().Format("20060102-150405")
+			saved := 0
+
+			for i, msg := range messages {
+				tc, err := testCase(msg, options.Vhost)
+				if err != nil {
+					output.Printf("⚠️  [%d/%d] Mensagem ignorada: %v\n", i+1, len(messages), err)
+					continue
+				}
+
+				tc.Name = availableName(outputDir, prefix+"-"+stamp, i+1)
+				testPath := filepath.Join(outputDir, tc.Name+".json")
+
+				data, err := json.MarshalIndent(tc, "", "  ")
+				if err != nil {
+					output.Printf("⚠️  [%d/%d] Erro ao serializar caso de teste: %v\n", i+1, len(messages), err)
+					continue
+				}
+
+				if err := os.WriteFile(testPath, data, 0644); err != nil {
+					output.Printf("⚠️  [%d/%d] Erro ao salvar teste: %v\n", i+1, len(messages), err)
+					continue
+				}
+
+				saved++
+
+				output.Printf("💾 [%d/%d] %s (route: %s)\n", i+1, len(messages), tc.Name, tc.RouteKey)
+			}
+
+			// Atualiza o cache para que os testes gravados apareçam no autocomplete
+			r.Cache.SyncCacheWithFileSystem()
+
+			output.Println("─────────────────────────────────────")
+			output.Printf("✅ %d caso(s) de teste gravado(s) em %s\n", saved, outputDir)
+
+			switch {
+			case saved == 0:
+				return fmt.Errorf("nenhuma das %d mensagem(ns) foi gravada", len(messages))
+			case saved < len(messages):
+				return exitcode.New(exitcode.Partial, "%d de %d mensagem(ns) não foram gravadas",
+					len(messages)-saved, len(messages))
+			default:
+				return nil
+			}
+		},
+	}
+
+	cmd.Flags().IntVarP(&options.Count, "count", "n", 1,
+		"Quantidade máxima de mensagens gravadas")
+	cmd.Flags().BoolVar(&options.Requeue, "requeue", true,
+		"Recoloca as mensagens na fila após a leitura")
+	cmd.Flags().StringVar(&options.Vhost, "vhost", "",
+		"Virtual host da fila (padrão: o da configuração ativa)")
+	cmd.Flags().StringVar(&options.Transport, "transport", "",
+		"Transporte usado na leitura: http (API de gerenciamento) ou amqp")
+	cmd.Flags().StringVar(&prefix, "prefix", "",
+		"Prefixo do nome dos testes gravados (padrão: nome da fila)")
+
+	return cmd
+}
+
+// testCase converte uma mensagem lida da fila em um caso de teste reexecutável
+func testCase(msg request.Message, vhost string) (rabbix.TestCase, error) {
+	body, err := msg.Body()
+	if err != nil {
+		return rabbix.TestCase{}, err
+	}
+
+	tc := rabbix.TestCase{
+		RouteKey: msg.RoutingKey,
+		Exchange: msg.Exchange,
+		Vhost:    vhost,
+		Headers:  msg.Headers(),
+	}
+
+	// Sem o exchange explícito a reexecução usaria o exchange da configuração ativa
+	if tc.Exchange == "" {
+		tc.Exchange = rabbix.DefaultExchange
+	}
+
+	tc.JSONPool, tc.Payload = rabbix.DetectPayload(body)
+
+	// No json_pool, os trechos {{...}} seriam avaliados como placeholders na reexecução
+	if tc.JSONPool != nil && bytes.Contains(body, []byte("{{")) {
+		tc.JSONPool, tc.Payload = nil, &rabbix.Payload{JSON: json.RawMessage(bytes.TrimSpace(body))}
+	}
+
+	properties := map[string]any{}
+	for key, value := range msg.Properties {
+		if !discardedProperties[key] {
+			properties[key] = value
+		}
+	}
+
+	if len(properties) > 0 {
+		// As chaves da API de gerenciamento são as mesmas das properties do caso de teste
+		data, _ := json.Marshal(properties)
+		if err := json.Unmarshal(data, &tc.Properties); err != nil {
+			return rabbix.TestCase{}, fmt.Errorf("properties inválidas: %w", err)
+		}
+	}
+
+	return tc, nil
+}
+
+// availableName gera um nome de teste que ainda não existe no diretório
+func availableName(outputDir, base string, index int) string {
+	name := base + "-" + strconv.Itoa(index)
+
+	for attempt := 2; ; attempt++ {
+		if _, err := os.Stat(filepath.Join(outputDir, name+".json")); os.IsNotExist(err) {
+			return name
+		}
+
+		name = base + "-" + strconv.Itoa(index) + "-" + strconv.Itoa(attempt)
+	}
+}
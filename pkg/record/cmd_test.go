@@ -0,0 +1,64 @@
+package record
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	"github.com/maxwelbm/rabbix/pkg/request"
+)
+
+func TestTestCase(t *testing.T) {
+	msg := request.Message{
+		RoutingKey: "pedidos.criado",
+		Properties: map[string]any{
+			"content_type":   "application/json",
+			"correlation_id": "pedido-1",
+			"delivery_mode":  2,
+			"user_id":        "produtor",
+			"timestamp":      1700000000,
+			"message_id":     "b6f1",
+			"headers":        map[string]any{"origem": "loja"},
+		},
+		Payload:         `{"id":1,"status":"criado"}`,
+		PayloadEncoding: "string",
+	}
+
+	tc, err := testCase(msg, "/loja")
+	if err != nil {
+		t.Fatalf("testCase: %v", err)
+	}
+
+	want := rabbix.TestCase{
+		RouteKey: "pedidos.criado",
+		Exchange: rabbix.DefaultExchange,
+		Vhost:    "/loja",
+		Headers:  map[string]any{"origem": "loja"},
+		JSONPool: map[string]any{"id": float64(1), "status": "criado"},
+		Properties: &rabbix.Properties{
+			ContentType:   "application/json",
+			CorrelationID: "pedido-1",
+			DeliveryMode:  2,
+		},
+	}
+
+	if !reflect.DeepEqual(tc, want) {
+		t.Errorf("testCase = %+v (properties %+v), esperado %+v (properties %+v)",
+			tc, tc.Properties, want, want.Properties)
+	}
+}
+
+func TestTestCaseKeepsTemplateMarkers(t *testing.T) {
+	// O corpo gravado precisa ser reexecutado sem avaliar os trechos {{...}}
+	body := `{"mensagem":"Olá {{nome}}","id":1}`
+	msg := request.Message{RoutingKey: "avisos", Payload: body, PayloadEncoding: "string"}
+
+	tc, err := testCase(msg, "/")
+	if err != nil {
+		t.Fatalf("testCase: %v", err)
+	}
+
+	if tc.JSONPool != nil || tc.Payload == nil || string(tc.Payload.JSON) != body {
+		t.Errorf("testCase = json_pool %v, payload %+v; esperado payload.json com o corpo original", tc.JSONPool, tc.Payload)
+	}
+}
@@ -0,0 +1,664 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	"github.com/maxwelbm/rabbix/pkg/sett"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// dialTimeout limita a conexão e o handshake AMQP quando o contexto não tem prazo
+const dialTimeout = 30 * time.Second
+
+// replyPrefetch limita as mensagens sem ack de uma fila de resposta existente
+// no AMQP e as mensagens lidas por consulta no HTTP.Call
+const replyPrefetch = 100
+
+// AMQP publica mensagens diretamente pelo protocolo AMQP 0-9-1, reutilizando
+// uma conexão e um canal por virtual host entre as publicações
+type AMQP struct {
+	settings sett.SettItf
+
+	mutex    sync.Mutex
+	sessions map[string]*amqpSession
+}
+
+var _ RequestItf = (*AMQP)(nil)
+
+// amqpSession é um canal em modo confirm com a notificação de mensagens devolvidas
+type amqpSession struct {
+	mutex   sync.Mutex
+	conn    *amqp.Connection
+	channel *amqp.Channel
+	returns chan amqp.Return
+}
+
+func NewAMQP(settings sett.SettItf) *AMQP {
+	return &AMQP{
+		settings: settings,
+		sessions: map[string]*amqpSession{},
+	}
+}
+
+// Request publica o caso de teste e aguarda a confirmação do broker. A resposta
+// segue o formato da API HTTP de gerenciamento para que os comandos tratem os
+// dois transportes da mesma forma
+func (a *AMQP) Request(ctx context.Context, testCase rabbix.TestCase) (*http.Response, error) {
+	settings := a.settings.LoadSettings()
+
+	uri, err := vhostURI(settings, testCase.Vhost)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange, _ := destination(settings, testCase)
+	if exchange == rabbix.DefaultExchange {
+		exchange = ""
+	}
+
+	body, binary, err := payload(settings, testCase)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := amqpTable(testCase.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("headers inválidos para AMQP: %w", err)
+	}
+
+	session, err := a.session(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := publishing(testCase.Properties, contentType(testCase, binary))
+	msg.Headers = headers
+	msg.Body = body
+
+	routed, err := session.publish(ctx, exchange, testCase.RouteKey, msg)
+	if err != nil {
+		a.discard(uri.String())
+		return nil, err
+	}
+
+	responseBody, _ := json.Marshal(map[string]bool{"routed": routed})
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(responseBody)),
+	}, nil
+}
+
+// Get lê mensagens com basic.get. As mensagens só são liquidadas depois de
+// todas lidas, senão as recolocadas na fila seriam lidas novamente
+func (a *AMQP) Get(ctx context.Context, options GetOptions) ([]Message, error) {
+	settings := a.settings.LoadSettings()
+
+	if _, err := options.ackMode(); err != nil {
+		return nil, err
+	}
+
+	uri, err := vhostURI(settings, options.Vhost)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := a.session(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := session.get(ctx, options.Queue, max(options.Count, 1))
+	if err != nil {
+		a.discard(uri.String())
+		return nil, err
+	}
+
+	messages := make([]Message, 0, len(deliveries))
+
+	for _, delivery := range deliveries {
+		messages = append(messages, deliveryMessage(delivery))
+
+		var err error
+
+		switch {
+		case strings.EqualFold(options.AckMode, AckModeReject):
+			err = delivery.Reject(options.Requeue)
+		case options.Requeue:
+			err = delivery.Nack(false, true)
+		default:
+			err = delivery.Ack(false)
+		}
+
+		if err != nil {
+			return messages, fmt.Errorf("erro ao confirmar mensagem: %w", err)
+		}
+	}
+
+	return messages, nil
+}
+
+// Purge remove as mensagens prontas da fila com queue.purge
+func (a *AMQP) Purge(ctx context.Context, options PurgeOptions) error {
+	settings := a.settings.LoadSettings()
+
+	uri, err := vhostURI(settings, options.Vhost)
+	if err != nil {
+		return err
+	}
+
+	session, err := a.session(ctx, uri)
+	if err != nil {
+		return err
+	}
+
+	if err := session.purge(options.Queue); err != nil {
+		a.discard(uri.String())
+		return err
+	}
+
+	return nil
+}
+
+// Call publica o caso de teste com reply_to e consome a fila de resposta até
+// receber a mensagem com o mesmo correlation_id. Sem fila informada, declara
+// uma fila exclusiva que o broker remove ao fechar o canal
+func (a *AMQP) Call(ctx context.Context, testCase rabbix.TestCase, options CallOptions) (Reply, error) {
+	settings := a.settings.LoadSettings()
+	reply := Reply{ReplyQueue: options.ReplyQueue}
+
+	uri, err := vhostURI(settings, testCase.Vhost)
+	if err != nil {
+		return reply, err
+	}
+
+	session, err := a.session(ctx, uri)
+	if err != nil {
+		return reply, err
+	}
+
+	// Canal próprio para o consumo, o canal da sessão segue em modo confirm
+	channel, err := session.conn.Channel()
+	if err != nil {
+		a.discard(uri.String())
+		return reply, fmt.Errorf("erro ao abrir canal AMQP: %w", err)
+	}
+
+	defer func() {
+		_ = channel.Close()
+	}()
+
+	temporary := reply.ReplyQueue == ""
+	if temporary {
+		queue, err := channel.QueueDeclare("", false, true, true, false, nil)
+		if err != nil {
+			return reply, fmt.Errorf("erro ao criar fila de resposta: %w", err)
+		}
+
+		reply.ReplyQueue = queue.Name
+	}
+
+	// Em uma fila existente as respostas de outras chamadas ficam sem ack até o fim
+	// da chamada e voltam para a fila quando o canal é fechado. Recolocá-las na hora
+	// faria o broker reentregá-las em seguida ao mesmo consumidor, em loop
+	if !temporary {
+		if err := channel.Qos(replyPrefetch, 0, false); err != nil {
+			return reply, fmt.Errorf("erro ao configurar prefetch da fila de resposta: %w", err)
+		}
+	}
+
+	deliveries, err := channel.Consume(reply.ReplyQueue, "", false, temporary, false, false, nil)
+	if err != nil {
+		return reply, fmt.Errorf("erro ao consumir fila de resposta '%s': %w", reply.ReplyQueue, err)
+	}
+
+	msg, correlationID := prepareCall(testCase, reply.ReplyQueue)
+	reply.CorrelationID = correlationID
+
+	start := time.Now()
+
+	resp, err := a.Request(ctx, msg)
+	if err != nil {
+		return reply, err
+	}
+
+	if reply.Routed, err = checkPublish(resp, options.AllowUnroutable); err != nil {
+		return reply, err
+	}
+
+	timeout := time.NewTimer(options.Timeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case delivery, ok := <-deliveries:
+			if !ok {
+				return reply, errors.New("canal de resposta fechado pelo broker")
+			}
+
+			// A fila temporária usa auto ack; nas existentes o ack é só da resposta
+			if delivery.CorrelationId != correlationID {
+				continue
+			}
+
+			reply.Latency = time.Since(start)
+			reply.Message = deliveryMessage(delivery)
+
+			if !temporary {
+				_ = delivery.Ack(false)
+			}
+
+			return reply, nil
+		case <-timeout.C:
+			return reply, ErrReplyTimeout
+		case <-ctx.Done():
+			return reply, context.Cause(ctx)
+		}
+	}
+}
+
+// Close encerra todas as conexões abertas
+func (a *AMQP) Close() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	var errs []error
+
+	for key, session := range a.sessions {
+		errs = append(errs, session.close())
+		delete(a.sessions, key)
+	}
+
+	return errors.Join(errs...)
+}
+
+// session retorna a sessão aberta para a URL ou abre uma nova
+func (a *AMQP) session(ctx context.Context, uri amqp.URI) (*amqpSession, error) {
+	key := uri.String()
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if session, ok := a.sessions[key]; ok && !session.channel.IsClosed() {
+		return session, nil
+	}
+
+	conn, err := amqp.DialConfig(key, amqp.Config{Locale: "en_US", Dial: dialContext(ctx)})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar via AMQP em %s:%d%s: %w", uri.Host, uri.Port, uri.Vhost, err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("erro ao abrir canal AMQP: %w", err)
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("erro ao ativar confirmações de publicação: %w", err)
+	}
+
+	session := &amqpSession{
+		conn:    conn,
+		channel: channel,
+		returns: channel.NotifyReturn(make(chan amqp.Return, 1)),
+	}
+	a.sessions[key] = session
+
+	return session, nil
+}
+
+// discard fecha e remove uma sessão com erro para que a próxima publicação reconecte
+func (a *AMQP) discard(key string) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if session, ok := a.sessions[key]; ok {
+		_ = session.close()
+		delete(a.sessions, key)
+	}
+}
+
+// publish envia a mensagem como mandatory e retorna se ela foi roteada para alguma fila.
+// As publicações são serializadas para associar cada basic.return à mensagem correta
+// Uma publicação cancelada antes da confirmação deixa a sessão inconsistente e
+// é descartada por quem chamou
+func (s *amqpSession) publish(ctx context.Context, exchange, routeKey string, msg amqp.Publishing) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	confirmation, err := s.channel.PublishWithDeferredConfirmWithContext(
+		ctx, exchange, routeKey, true, false, msg)
+	if err != nil {
+		return false, fmt.Errorf("erro ao publicar via AMQP: %w", err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("erro ao aguardar confirmação do broker: %w", err)
+	}
+
+	if !acked {
+		return false, ErrNack
+	}
+
+	// O broker envia o basic.return antes da confirmação, então ele já está disponível aqui
+	select {
+	case <-s.returns:
+		return false, nil
+	default:
+		return true, nil
+	}
+}
+
+// get lê até count mensagens da fila sem confirmá-las
+func (s *amqpSession) get(ctx context.Context, queue string, count int) ([]amqp.Delivery, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var deliveries []amqp.Delivery
+
+	for len(deliveries) < count {
+		// basic.get não aceita contexto, então o cancelamento é verificado a cada mensagem
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("erro ao ler fila '%s': %w", queue, context.Cause(ctx))
+		}
+
+		delivery, ok, err := s.channel.Get(queue, false)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler fila '%s': %w", queue, err)
+		}
+
+		if !ok {
+			break
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (s *amqpSession) purge(queue string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err := s.channel.QueuePurge(queue, false); err != nil {
+		return fmt.Errorf("erro ao esvaziar fila '%s': %w", queue, err)
+	}
+
+	return nil
+}
+
+// dialContext abre a conexão TCP respeitando o cancelamento do contexto. O prazo
+// vale também para o handshake AMQP, e a biblioteca o remove depois dele
+func dialContext(ctx context.Context) func(network, addr string) (net.Conn, error) {
+	return func(network, addr string) (net.Conn, error) {
+		deadline := time.Now().Add(dialTimeout)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
+
+		dialer := net.Dialer{Deadline: deadline}
+
+		conn, err := dialer.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := conn.SetDeadline(deadline); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+
+		return conn, nil
+	}
+}
+
+func (s *amqpSession) close() error {
+	if s.conn.IsClosed() {
+		return nil
+	}
+
+	return s.conn.Close()
+}
+
+// contentType define o content_type usado quando o caso de teste não informa um
+func contentType(testCase rabbix.TestCase, binary bool) string {
+	switch {
+	case testCase.Payload == nil || len(testCase.Payload.JSON) > 0:
+		return "application/json"
+	case binary || testCase.Payload.Base64 != "":
+		return "application/octet-stream"
+	default:
+		return "text/plain"
+	}
+}
+
+// publishing converte as properties do caso de teste para a mensagem AMQP
+func publishing(properties *rabbix.Properties, defaultContentType string) amqp.Publishing {
+	msg := amqp.Publishing{ContentType: defaultContentType}
+	if properties == nil {
+		return msg
+	}
+
+	if properties.ContentType != "" {
+		msg.ContentType = properties.ContentType
+	}
+
+	msg.DeliveryMode = properties.DeliveryMode
+	msg.Priority = properties.Priority
+	msg.CorrelationId = properties.CorrelationID
+	msg.MessageId = properties.MessageID
+	msg.ReplyTo = properties.ReplyTo
+	msg.Expiration = properties.Expiration
+	msg.Type = properties.Type
+	msg.AppId = properties.AppID
+	msg.UserId = properties.UserID
+
+	if properties.Timestamp > 0 {
+		msg.Timestamp = time.Unix(properties.Timestamp, 0)
+	}
+
+	return msg
+}
+
+// vhostURI resolve a URL AMQP para o vhost informado. O vhost do AMQP faz parte
+// da conexão, então só sobrescreve o da URL quando informado ou configurado
+func vhostURI(settings map[string]string, vhost string) (amqp.URI, error) {
+	uri, err := amqpURI(settings)
+	if err != nil {
+		return uri, err
+	}
+
+	if vhost != "" {
+		uri.Vhost = vhost
+	} else if settings["vhost"] != "" {
+		uri.Vhost = settings["vhost"]
+	}
+
+	return uri, nil
+}
+
+// amqpURI resolve a URL AMQP da configuração ativa. Sem 'amqp_url', deriva a
+// URL do host do plugin de gerenciamento e das credenciais configuradas
+func amqpURI(settings map[string]string) (amqp.URI, error) {
+	if raw := settings["amqp_url"]; raw != "" {
+		uri, err := amqp.ParseURI(raw)
+		if err != nil {
+			return amqp.URI{}, fmt.Errorf("amqp_url inválida: %w", err)
+		}
+
+		return uri, nil
+	}
+
+	var host = "http://localhost:15672" // host default
+	if settings["host"] != "" {
+		host = settings["host"]
+	}
+
+	parsed, err := url.Parse(host)
+	if err != nil {
+		return amqp.URI{}, fmt.Errorf("host inválido '%s': %w", host, err)
+	}
+
+	raw := "amqp://"
+	port := 5672
+
+	if parsed.Scheme == "https" {
+		raw = "amqps://"
+		port = 5671
+	}
+
+	if credentials, err := base64.StdEncoding.DecodeString(settings["auth"]); err == nil && len(credentials) > 0 {
+		user, password, _ := strings.Cut(string(credentials), ":")
+		raw += url.UserPassword(user, password).String() + "@"
+	}
+
+	raw += parsed.Hostname() + ":" + strconv.Itoa(port) + "/"
+
+	uri, err := amqp.ParseURI(raw)
+	if err != nil {
+		return amqp.URI{}, fmt.Errorf("não foi possível derivar a URL AMQP do host '%s': %w", host, err)
+	}
+
+	return uri, nil
+}
+
+// amqpTable converte os headers do caso de teste em uma tabela AMQP válida
+func amqpTable(headers map[string]any) (amqp.Table, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	table := amqp.Table{}
+	for key, value := range headers {
+		table[key] = amqpValue(value)
+	}
+
+	return table, table.Validate()
+}
+
+// amqpValue converte objetos JSON aninhados em tabelas AMQP. Números lidos com
+// UseNumber, como os dos arquivos de dados, viram int64 ou float64, já que a
+// tabela AMQP não aceita json.Number
+func amqpValue(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+
+		return v.String()
+	case map[string]any:
+		table := amqp.Table{}
+		for key, item := range v {
+			table[key] = amqpValue(item)
+		}
+
+		return table
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = amqpValue(item)
+		}
+
+		return items
+	default:
+		return v
+	}
+}
+
+// deliveryMessage converte uma mensagem AMQP para o formato da API de gerenciamento
+func deliveryMessage(delivery amqp.Delivery) Message {
+	properties := map[string]any{}
+
+	set := func(key string, value any, present bool) {
+		if present {
+			properties[key] = value
+		}
+	}
+
+	set("content_type", delivery.ContentType, delivery.ContentType != "")
+	set("content_encoding", delivery.ContentEncoding, delivery.ContentEncoding != "")
+	set("delivery_mode", delivery.DeliveryMode, delivery.DeliveryMode != 0)
+	set("priority", delivery.Priority, delivery.Priority != 0)
+	set("correlation_id", delivery.CorrelationId, delivery.CorrelationId != "")
+	set("reply_to", delivery.ReplyTo, delivery.ReplyTo != "")
+	set("expiration", delivery.Expiration, delivery.Expiration != "")
+	set("message_id", delivery.MessageId, delivery.MessageId != "")
+	set("timestamp", delivery.Timestamp.Unix(), !delivery.Timestamp.IsZero())
+	set("type", delivery.Type, delivery.Type != "")
+	set("user_id", delivery.UserId, delivery.UserId != "")
+	set("app_id", delivery.AppId, delivery.AppId != "")
+
+	if len(delivery.Headers) > 0 {
+		properties["headers"] = jsonValue(delivery.Headers)
+	}
+
+	msg := Message{
+		Exchange:        delivery.Exchange,
+		RoutingKey:      delivery.RoutingKey,
+		Redelivered:     delivery.Redelivered,
+		MessageCount:    int(delivery.MessageCount),
+		Properties:      properties,
+		Payload:         string(delivery.Body),
+		PayloadEncoding: "string",
+		PayloadBytes:    len(delivery.Body),
+	}
+
+	if !utf8.Valid(delivery.Body) {
+		msg.Payload = base64.StdEncoding.EncodeToString(delivery.Body)
+		msg.PayloadEncoding = "base64"
+	}
+
+	return msg
+}
+
+// jsonValue converte tabelas AMQP de volta para valores JSON
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case amqp.Table:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = jsonValue(item)
+		}
+
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = jsonValue(item)
+		}
+
+		return out
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Unix()
+	default:
+		return v
+	}
+}
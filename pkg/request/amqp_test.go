@@ -0,0 +1,60 @@
+package request
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/maxwelbm/rabbix/pkg/dataset"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestAMQPTableFromDatasetHeaders(t *testing.T) {
+	// Os números do JSON Lines chegam como json.Number, que a tabela AMQP rejeita
+	path := filepath.Join(t.TempDir(), "pedidos.jsonl")
+	line := `{"headers.retry": 3, "headers.ratio": 0.5, "headers.big": 1e400,` +
+		` "headers.meta": {"shard": 7, "tags": [1, "a"]}, "headers.tenant": "acme"}`
+
+	if err := os.WriteFile(path, []byte(line+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := dataset.Load(path)
+	if err != nil {
+		t.Fatalf("dataset.Load: %v", err)
+	}
+
+	tc, err := rows[0].Apply(rabbix.TestCase{Name: "pedido", RouteKey: "pedidos"})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	table, err := amqpTable(tc.Headers)
+	if err != nil {
+		t.Fatalf("amqpTable rejeitou os headers do arquivo de dados: %v", err)
+	}
+
+	want := amqp.Table{
+		"retry":  int64(3),
+		"ratio":  0.5,
+		"big":    "1e400",
+		"meta":   amqp.Table{"shard": int64(7), "tags": []any{int64(1), "a"}},
+		"tenant": "acme",
+	}
+
+	if !reflect.DeepEqual(table, want) {
+		t.Errorf("amqpTable = %#v, esperado %#v", table, want)
+	}
+}
+
+func TestAMQPTableRejectsUnsupportedValues(t *testing.T) {
+	if _, err := amqpTable(map[string]any{"canal": make(chan int)}); err == nil {
+		t.Error("amqpTable deveria rejeitar valores sem tipo AMQP")
+	}
+
+	if table, err := amqpTable(nil); table != nil || err != nil {
+		t.Errorf("amqpTable(nil) = %#v, %v", table, err)
+	}
+}
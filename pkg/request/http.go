@@ -0,0 +1,373 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	"github.com/maxwelbm/rabbix/pkg/sett"
+)
+
+// HTTP publica mensagens pela API HTTP do plugin de gerenciamento
+type HTTP struct {
+	settings sett.SettItf
+}
+
+var _ RequestItf = (*HTTP)(nil)
+
+func NewHTTP(settings sett.SettItf) *HTTP {
+	return &HTTP{
+		settings: settings,
+	}
+}
+
+// Request envia uma mensagem para o RabbitMQ usando a API HTTP
+func (r *HTTP) Request(ctx context.Context, testCase rabbix.TestCase) (*http.Response, error) {
+	settings := r.settings.LoadSettings()
+
+	payloadBytes, binary, err := payload(settings, testCase)
+	if err != nil {
+		return nil, err
+	}
+
+	// Corpos binários não sobrevivem a uma string JSON, então seguem em base64
+	payloadString, payloadEncoding := string(payloadBytes), "string"
+	if binary {
+		payloadString, payloadEncoding = base64.StdEncoding.EncodeToString(payloadBytes), "base64"
+	}
+
+	properties := map[string]any{}
+	if testCase.Properties != nil {
+		// Reaproveita as tags JSON, que seguem os nomes esperados pela API de gerenciamento
+		propertiesBytes, err := json.Marshal(testCase.Properties)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao serializar properties: %w", err)
+		}
+
+		if err := json.Unmarshal(propertiesBytes, &properties); err != nil {
+			return nil, fmt.Errorf("erro ao serializar properties: %w", err)
+		}
+	}
+
+	if len(testCase.Headers) > 0 {
+		properties["headers"] = testCase.Headers
+	}
+
+	requestBody := map[string]any{
+		"properties":       properties,
+		"routing_key":      testCase.RouteKey,
+		"payload":          payloadString,
+		"payload_encoding": payloadEncoding,
+	}
+
+	finalBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar request body: %w", err)
+	}
+
+	exchange, vhost := destination(settings, testCase)
+
+	path := "/api/exchanges/" + url.PathEscape(vhost) + "/" + url.PathEscape(exchange) + "/publish"
+
+	req, err := NewManagementRequest(ctx, settings, "POST", path, bytes.NewBuffer(finalBody))
+	if err != nil {
+		return nil, err
+	}
+
+	return managementClient.Do(req)
+}
+
+// Close não possui recursos para liberar, cada publicação é uma requisição
+func (r *HTTP) Close() error {
+	return nil
+}
+
+// Get lê mensagens de uma fila pelo endpoint /api/queues/{vhost}/{fila}/get
+func (r *HTTP) Get(ctx context.Context, options GetOptions) ([]Message, error) {
+	settings := r.settings.LoadSettings()
+
+	ackMode, err := options.ackMode()
+	if err != nil {
+		return nil, err
+	}
+
+	requestBody, err := json.Marshal(map[string]any{
+		"count":    max(options.Count, 1),
+		"ackmode":  ackMode,
+		"encoding": "auto",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar request body: %w", err)
+	}
+
+	path := queuePath(resolveVhost(settings, options.Vhost), options.Queue) + "/get"
+
+	req, err := NewManagementRequest(ctx, settings, "POST", path, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := managementClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler fila '%s': %w", options.Queue, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler resposta: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("erro ao ler fila '%s' (status %d): %s", options.Queue, resp.StatusCode, string(body))
+	}
+
+	var messages []Message
+	if err := json.Unmarshal(body, &messages); err != nil {
+		return nil, fmt.Errorf("resposta inesperada do RabbitMQ: %w", err)
+	}
+
+	return messages, nil
+}
+
+// Call publica o caso de teste com reply_to e aguarda a resposta consultando a
+// fila pela API de gerenciamento. Sem fila informada, usa uma fila temporária
+// que expira sozinha caso a CLI seja interrompida. Em uma fila existente, apenas
+// a resposta da chamada é consumida
+func (r *HTTP) Call(ctx context.Context, testCase rabbix.TestCase, options CallOptions) (Reply, error) {
+	settings := r.settings.LoadSettings()
+	vhost := resolveVhost(settings, testCase.Vhost)
+
+	reply := Reply{ReplyQueue: options.ReplyQueue}
+	temporary := reply.ReplyQueue == ""
+
+	if temporary {
+		reply.ReplyQueue = "rabbix.reply." + newID()
+
+		if err := r.queueRequest(ctx, settings, "PUT", queuePath(vhost, reply.ReplyQueue), map[string]any{
+			"durable":     false,
+			"auto_delete": false,
+			"arguments":   map[string]any{"x-expires": (options.Timeout + time.Minute).Milliseconds()},
+		}); err != nil {
+			return reply, fmt.Errorf("erro ao criar fila de resposta: %w", err)
+		}
+
+		// A remoção acontece mesmo com a chamada cancelada
+		defer func() {
+			_ = r.queueRequest(context.WithoutCancel(ctx), settings, "DELETE", queuePath(vhost, reply.ReplyQueue), nil)
+		}()
+	}
+
+	msg, correlationID := prepareCall(testCase, reply.ReplyQueue)
+	reply.CorrelationID = correlationID
+
+	start := time.Now()
+
+	resp, err := r.Request(ctx, msg)
+	if err != nil {
+		return reply, err
+	}
+
+	if reply.Routed, err = checkPublish(resp, options.AllowUnroutable); err != nil {
+		return reply, err
+	}
+
+	deadline := start.Add(options.Timeout)
+	for time.Now().Before(deadline) {
+		// Na fila temporária todas as mensagens são respostas e podem ser consumidas;
+		// em uma fila existente a leitura só observa, e a resposta é consumida depois
+		messages, err := r.Get(ctx, GetOptions{
+			Queue:   reply.ReplyQueue,
+			Vhost:   vhost,
+			Count:   replyPrefetch,
+			AckMode: AckModeAck,
+			Requeue: !temporary,
+		})
+		if err != nil {
+			return reply, err
+		}
+
+		for i, message := range messages {
+			if message.Properties["correlation_id"] != correlationID {
+				continue
+			}
+
+			if !temporary {
+				if message, err = r.consumeReply(ctx, settings, vhost, reply.ReplyQueue, correlationID, i+1); err != nil {
+					return reply, err
+				}
+
+				// Outro consumidor levou a resposta entre a leitura e o consumo
+				if message.Properties == nil {
+					break
+				}
+			}
+
+			reply.Latency = time.Since(start)
+			reply.Message = message
+
+			return reply, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return reply, context.Cause(ctx)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+
+	return reply, ErrReplyTimeout
+}
+
+// consumeReply consome as count primeiras mensagens de uma fila de resposta
+// existente, onde está a resposta encontrada, e devolve à fila as respostas de
+// outras chamadas. A API de gerenciamento só consome a partir do início da fila,
+// então as mensagens anteriores à resposta voltam para o fim dela. Retorna uma
+// mensagem vazia se a resposta não estiver mais entre as consumidas
+func (r *HTTP) consumeReply(
+	ctx context.Context,
+	settings map[string]string,
+	vhost, queue, correlationID string,
+	count int,
+) (Message, error) {
+	messages, err := r.Get(ctx, GetOptions{Queue: queue, Vhost: vhost, Count: count, AckMode: AckModeAck})
+	if err != nil {
+		return Message{}, err
+	}
+
+	var (
+		reply Message
+		errs  []error
+	)
+
+	for _, message := range messages {
+		if reply.Properties == nil && message.Properties["correlation_id"] == correlationID {
+			reply = message
+			continue
+		}
+
+		// A devolução não pode ser cancelada, ou a mensagem se perderia
+		if err := r.republish(context.WithoutCancel(ctx), settings, vhost, queue, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return reply, fmt.Errorf("erro ao devolver mensagens à fila de resposta '%s': %w", queue, err)
+	}
+
+	return reply, nil
+}
+
+// republish publica a mensagem de volta na fila pelo exchange padrão,
+// preservando as propriedades e o payload lidos
+func (r *HTTP) republish(ctx context.Context, settings map[string]string, vhost, queue string, message Message) error {
+	properties := message.Properties
+	if properties == nil {
+		properties = map[string]any{}
+	}
+
+	data, err := json.Marshal(map[string]any{
+		"properties":       properties,
+		"routing_key":      queue,
+		"payload":          message.Payload,
+		"payload_encoding": message.PayloadEncoding,
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao serializar request body: %w", err)
+	}
+
+	path := "/api/exchanges/" + url.PathEscape(vhost) + "/" + url.PathEscape(rabbix.DefaultExchange) + "/publish"
+
+	req, err := NewManagementRequest(ctx, settings, "POST", path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := managementClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if result, err := ParseResult(body); err != nil || !result.Routed {
+		return fmt.Errorf("mensagem não devolvida à fila '%s'", queue)
+	}
+
+	return nil
+}
+
+// Purge remove as mensagens da fila pelo endpoint /api/queues/{vhost}/{fila}/contents
+func (r *HTTP) Purge(ctx context.Context, options PurgeOptions) error {
+	settings := r.settings.LoadSettings()
+	path := queuePath(resolveVhost(settings, options.Vhost), options.Queue) + "/contents"
+
+	if err := r.queueRequest(ctx, settings, "DELETE", path, nil); err != nil {
+		return fmt.Errorf("erro ao esvaziar fila '%s': %w", options.Queue, err)
+	}
+
+	return nil
+}
+
+// queuePath monta o caminho de uma fila na API de gerenciamento
+func queuePath(vhost, queue string) string {
+	return "/api/queues/" + url.PathEscape(vhost) + "/" + url.PathEscape(queue)
+}
+
+// queueRequest declara, remove ou esvazia uma fila pela API de gerenciamento
+func (r *HTTP) queueRequest(
+	ctx context.Context,
+	settings map[string]string,
+	method, path string,
+	body any,
+) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := NewManagementRequest(ctx, settings, method, path, reader)
+	if err != nil {
+		return err
+	}
+
+	resp, err := managementClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("status %d: %s", resp.StatusCode, string(data))
+	}
+
+	return nil
+}
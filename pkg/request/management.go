@@ -0,0 +1,84 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// managementDialTimeout limita a conexão TCP com a API de gerenciamento
+	managementDialTimeout = 10 * time.Second
+	// managementHeaderTimeout limita a espera pelos cabeçalhos da resposta, para
+	// que um broker travado não prenda get, record e health indefinidamente
+	managementHeaderTimeout = 60 * time.Second
+)
+
+// managementClient é compartilhado por todas as chamadas à API de gerenciamento,
+// reaproveitando as conexões entre publicações
+var managementClient = newManagementClient()
+
+// ErrAuthNotConfigured indica que a configuração ativa não possui credenciais
+var ErrAuthNotConfigured = errors.New("autenticação não configurada, " +
+	"use o comando 'rabbix conf set --user <user> --password <password>'")
+
+// ManagementURL monta a URL de um endpoint da API HTTP de gerenciamento
+func ManagementURL(settings map[string]string, path string) string {
+	var host = "http://localhost:15672" // host default
+	if settings["host"] != "" {
+		host = settings["host"]
+	}
+
+	return strings.TrimRight(host, "/") + path
+}
+
+// ManagementClient retorna o cliente HTTP usado nas chamadas à API de gerenciamento
+func ManagementClient() *http.Client {
+	return managementClient
+}
+
+func newManagementClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   managementDialTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.ResponseHeaderTimeout = managementHeaderTimeout
+	// Publicações concorrentes vão todas para o mesmo host
+	transport.MaxIdleConnsPerHost = 100
+
+	return &http.Client{Transport: transport}
+}
+
+// NewManagementRequest cria uma requisição autenticada para a API HTTP de
+// gerenciamento com o host e as credenciais da configuração ativa. O contexto
+// cancela a requisição e a leitura da resposta
+func NewManagementRequest(
+	ctx context.Context,
+	settings map[string]string,
+	method, path string,
+	body io.Reader,
+) (*http.Request, error) {
+	auth := settings["auth"]
+	if auth == "" {
+		return nil, ErrAuthNotConfigured
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, ManagementURL(settings, path), body)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição HTTP: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Basic "+auth)
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
@@ -0,0 +1,76 @@
+package request
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+const (
+	// AckModeAck confirma as mensagens lidas
+	AckModeAck = "ack"
+	// AckModeReject rejeita as mensagens lidas, enviando para a dead letter quando não recolocadas
+	AckModeReject = "reject"
+)
+
+// GetOptions define como as mensagens são lidas de uma fila
+type GetOptions struct {
+	Queue     string
+	Vhost     string
+	Transport string
+	Count     int
+	AckMode   string
+	Requeue   bool
+}
+
+// PurgeOptions identifica a fila esvaziada por Purge
+type PurgeOptions struct {
+	Queue     string
+	Vhost     string
+	Transport string
+}
+
+// Message é uma mensagem lida de uma fila, no formato da API de gerenciamento.
+// O transporte AMQP devolve o mesmo formato
+type Message struct {
+	Exchange        string         `json:"exchange"`
+	RoutingKey      string         `json:"routing_key"`
+	Redelivered     bool           `json:"redelivered"`
+	MessageCount    int            `json:"message_count"`
+	Properties      map[string]any `json:"properties"`
+	Payload         string         `json:"payload"`
+	PayloadEncoding string         `json:"payload_encoding"`
+	PayloadBytes    int            `json:"payload_bytes"`
+}
+
+// Body decodifica o payload da mensagem
+func (m Message) Body() ([]byte, error) {
+	if m.PayloadEncoding == "base64" {
+		body, err := base64.StdEncoding.DecodeString(m.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("payload base64 inválido: %w", err)
+		}
+
+		return body, nil
+	}
+
+	return []byte(m.Payload), nil
+}
+
+// Headers retorna os headers da mensagem, se houver
+func (m Message) Headers() map[string]any {
+	headers, _ := m.Properties["headers"].(map[string]any)
+	return headers
+}
+
+// ackMode converte as opções para o 'ackmode' da API de gerenciamento
+func (o GetOptions) ackMode() (string, error) {
+	switch strings.ToLower(o.AckMode) {
+	case "", AckModeAck:
+		return fmt.Sprintf("ack_requeue_%t", o.Requeue), nil
+	case AckModeReject:
+		return fmt.Sprintf("reject_requeue_%t", o.Requeue), nil
+	default:
+		return "", fmt.Errorf("modo de ack desconhecido '%s' (use '%s' ou '%s')", o.AckMode, AckModeAck, AckModeReject)
+	}
+}
@@ -1,22 +1,53 @@
 package request
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/maxwelbm/rabbix/pkg/rabbix"
 	"github.com/maxwelbm/rabbix/pkg/sett"
 )
 
+const (
+	// TransportHTTP publica pela API HTTP do plugin de gerenciamento
+	TransportHTTP = "http"
+	// TransportAMQP publica diretamente pelo protocolo AMQP 0-9-1
+	TransportAMQP = "amqp"
+)
+
+// RequestItf publica e lê mensagens; o contexto cancela a operação em andamento
+type RequestItf interface {
+	Request(ctx context.Context, testCase rabbix.TestCase) (*http.Response, error)
+	Get(ctx context.Context, options GetOptions) ([]Message, error)
+	Purge(ctx context.Context, options PurgeOptions) error
+	Call(ctx context.Context, testCase rabbix.TestCase, options CallOptions) (Reply, error)
+	Close() error
+}
+
+var _ RequestItf = (*Request)(nil)
+
+// ErrUnknownTransport indica um transporte diferente de http e amqp
+var ErrUnknownTransport = errors.New("transporte desconhecido")
+
+// IsConfigError indica se o erro vem da configuração ativa, e não do broker
+func IsConfigError(err error) bool {
+	return errors.Is(err, ErrAuthNotConfigured) || errors.Is(err, ErrUnknownTransport)
+}
+
+// Request escolhe o transporte de cada publicação e delega para ele,
+// mantendo as conexões abertas entre as iterações
 type Request struct {
 	settings sett.SettItf
-}
 
-type RequestItf interface {
-	Request(testCase rabbix.TestCase) (*http.Response, error)
+	mutex sync.Mutex
+	http  *HTTP
+	amqp  *AMQP
 }
 
 func New(settings sett.SettItf) RequestItf {
@@ -25,60 +56,133 @@ func New(settings sett.SettItf) RequestItf {
 	}
 }
 
-// PublishMessage envia uma mensagem para o RabbitMQ usando a API HTTP
-func (r *Request) Request(testCase rabbix.TestCase) (*http.Response, error) {
-	settings := r.settings.LoadSettings()
+func (r *Request) Request(ctx context.Context, testCase rabbix.TestCase) (*http.Response, error) {
+	if testCase.Properties != nil {
+		if err := testCase.Properties.Validate(); err != nil {
+			return nil, fmt.Errorf("properties inválidas: %w", err)
+		}
+	}
 
-	var auth = settings["auth"]
-	if auth == "" {
-		fmt.Printf("necessario configurar user e password com o comando 'rabbix conf set" + "" +
-			" --user <user> --password <password>'\n")
-		return nil, fmt.Errorf("autenticação não configurada")
+	transport, err := r.resolve(testCase.Transport)
+	if err != nil {
+		return nil, err
 	}
 
-	auth = "Basic " + auth
+	return transport.Request(ctx, testCase)
+}
 
-	var host = "http://localhost:15672" // host default
-	if settings["host"] != "" {
-		host = settings["host"]
+func (r *Request) Get(ctx context.Context, options GetOptions) ([]Message, error) {
+	transport, err := r.resolve(options.Transport)
+	if err != nil {
+		return nil, err
 	}
 
-	payloadBytes, err := json.Marshal(testCase.JSONPool)
+	return transport.Get(ctx, options)
+}
+
+func (r *Request) Purge(ctx context.Context, options PurgeOptions) error {
+	transport, err := r.resolve(options.Transport)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao serializar payload: %w", err)
+		return err
 	}
 
-	properties := map[string]any{}
-	if len(testCase.Headers) > 0 {
-		properties["headers"] = testCase.Headers
-	}
+	return transport.Purge(ctx, options)
+}
 
-	requestBody := map[string]any{
-		"properties":       properties,
-		"routing_key":      testCase.RouteKey,
-		"payload":          string(payloadBytes),
-		"payload_encoding": "string",
+func (r *Request) Call(ctx context.Context, testCase rabbix.TestCase, options CallOptions) (Reply, error) {
+	if testCase.Properties != nil {
+		if err := testCase.Properties.Validate(); err != nil {
+			return Reply{}, fmt.Errorf("properties inválidas: %w", err)
+		}
 	}
 
-	finalBody, err := json.Marshal(requestBody)
+	transport, err := r.resolve(testCase.Transport)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao serializar request body: %w", err)
+		return Reply{}, err
 	}
 
-	raptURL := strings.TrimRight(host, "/") + "/api/exchanges/%2f/amq.default/publish"
+	return transport.Call(ctx, testCase, options)
+}
 
-	req, err := http.NewRequest("POST", raptURL, bytes.NewBuffer(finalBody))
-	if err != nil {
-		return nil, fmt.Errorf("erro ao criar requisição HTTP: %w", err)
+// Close encerra as conexões abertas pelos transportes utilizados
+func (r *Request) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var errs []error
+
+	if r.http != nil {
+		errs = append(errs, r.http.Close())
 	}
 
-	if auth != "" {
-		req.Header.Set("Authorization", auth)
+	if r.amqp != nil {
+		errs = append(errs, r.amqp.Close())
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	return errors.Join(errs...)
+}
+
+// resolve escolhe o transporte: informado > configuração ativa > http
+func (r *Request) resolve(name string) (RequestItf, error) {
+	if name == "" {
+		name = r.settings.LoadSettings()["transport"]
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	switch strings.ToLower(name) {
+	case "", TransportHTTP:
+		if r.http == nil {
+			r.http = NewHTTP(r.settings)
+		}
+
+		return r.http, nil
+	case TransportAMQP:
+		if r.amqp == nil {
+			r.amqp = NewAMQP(r.settings)
+		}
+
+		return r.amqp, nil
+	default:
+		return nil, fmt.Errorf("%w '%s' (use '%s' ou '%s')", ErrUnknownTransport, name, TransportHTTP, TransportAMQP)
+	}
+}
+
+// destination resolve exchange e vhost: caso de teste > configuração ativa > padrão do RabbitMQ
+func destination(settings map[string]string, testCase rabbix.TestCase) (exchange, vhost string) {
+	exchange = testCase.Exchange
+	if exchange == "" {
+		exchange = settings["exchange"]
+	}
 
-	clientHttp := &http.Client{}
+	if exchange == "" {
+		exchange = rabbix.DefaultExchange
+	}
+
+	return exchange, resolveVhost(settings, testCase.Vhost)
+}
+
+// resolveVhost aplica a configuração ativa e o padrão do RabbitMQ a um vhost opcional
+func resolveVhost(settings map[string]string, vhost string) string {
+	if vhost == "" {
+		vhost = settings["vhost"]
+	}
+
+	if vhost == "" {
+		vhost = rabbix.DefaultVhost
+	}
+
+	return vhost
+}
+
+// payload monta o corpo da mensagem; arquivos relativos são resolvidos a partir do output_dir
+func payload(settings map[string]string, testCase rabbix.TestCase) ([]byte, bool, error) {
+	outputDir := settings["output_dir"]
+	if outputDir == "" {
+		home, _ := os.UserHomeDir()
+		outputDir = filepath.Join(home, ".rabbix", "tests")
+	}
 
-	return clientHttp.Do(req)
+	return testCase.Body(outputDir)
 }
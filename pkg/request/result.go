@@ -0,0 +1,24 @@
+package request
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// PublishResult é o corpo da resposta de publicação da API de gerenciamento.
+// O transporte AMQP devolve o mesmo formato
+type PublishResult struct {
+	Routed bool   `json:"routed"`
+	Error  string `json:"error,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ParseResult interpreta o corpo da resposta de uma publicação
+func ParseResult(body []byte) (PublishResult, error) {
+	var result PublishResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, fmt.Errorf("resposta inesperada do RabbitMQ: %w", err)
+	}
+
+	return result, nil
+}
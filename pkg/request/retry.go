@@ -0,0 +1,350 @@
+package request
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// RetryNetwork cobre conexões recusadas, resetadas ou encerradas pelo broker
+	RetryNetwork = "network"
+	// RetryTimeout cobre tempos limite de conexão e de resposta
+	RetryTimeout = "timeout"
+)
+
+// ErrNack indica que o broker rejeitou a publicação com basic.nack
+var ErrNack = errors.New("mensagem rejeitada pelo broker (nack)")
+
+// DefaultRetryOn são as falhas consideradas transitórias por padrão
+var DefaultRetryOn = []string{RetryNetwork, RetryTimeout, "429", "502", "503", "504"}
+
+// RetryPolicy define quantas vezes e quando uma publicação é repetida, e quanto
+// tempo cada tentativa pode levar
+type RetryPolicy struct {
+	// Timeout limita cada tentativa, da conexão à leitura da resposta (0 = sem limite)
+	Timeout time.Duration
+	// MaxAttempts é o total de tentativas, incluindo a primeira (1 = sem retentativas)
+	MaxAttempts int
+	// Backoff é a espera antes da segunda tentativa; dobra a cada nova tentativa
+	Backoff time.Duration
+	// MaxBackoff limita o crescimento exponencial da espera
+	MaxBackoff time.Duration
+	// Jitter é a variação aleatória da espera, como fração (0.2 = ±20%)
+	Jitter float64
+	// RetryOn lista as falhas repetidas: status HTTP ("503"), classes ("5xx"),
+	// "network" e "timeout"
+	RetryOn []string
+}
+
+// AddFlags registra as flags da política de retentativas no comando
+func (p *RetryPolicy) AddFlags(flags *pflag.FlagSet) {
+	flags.DurationVar(&p.Timeout, "timeout", 30*time.Second,
+		"Tempo limite de cada tentativa de publicação (0 = sem limite)")
+	flags.IntVar(&p.MaxAttempts, "max-attempts", 1,
+		"Total de tentativas por publicação em falhas transitórias (1 = sem retentativas)")
+	flags.DurationVar(&p.Backoff, "retry-backoff", 200*time.Millisecond,
+		"Espera antes da primeira retentativa; dobra a cada nova tentativa")
+	flags.DurationVar(&p.MaxBackoff, "retry-max-backoff", 5*time.Second,
+		"Espera máxima entre tentativas")
+	flags.Float64Var(&p.Jitter, "retry-jitter", 0.2,
+		"Variação aleatória da espera entre tentativas, como fração (0 a 1)")
+	flags.StringSliceVar(&p.RetryOn, "retry-on", DefaultRetryOn,
+		"Falhas repetidas: status HTTP (503), classes (5xx), network e timeout")
+}
+
+// Validate verifica os valores da política
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("--max-attempts deve ser maior ou igual a 1, recebido %d", p.MaxAttempts)
+	}
+
+	if p.Timeout < 0 {
+		return errors.New("--timeout não pode ser negativo")
+	}
+
+	if p.Backoff < 0 || p.MaxBackoff < 0 {
+		return errors.New("--retry-backoff e --retry-max-backoff não podem ser negativos")
+	}
+
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("--retry-jitter deve estar entre 0 e 1, recebido %v", p.Jitter)
+	}
+
+	for _, condition := range p.RetryOn {
+		switch condition = strings.ToLower(strings.TrimSpace(condition)); {
+		case condition == RetryNetwork, condition == RetryTimeout:
+		case len(condition) == 3 && strings.HasSuffix(condition, "xx") && condition[0] >= '1' && condition[0] <= '5':
+		default:
+			if status, err := strconv.Atoi(condition); err != nil || status < 100 || status > 599 {
+				return fmt.Errorf("condição de retentativa inválida '%s' (use status HTTP, 5xx, network ou timeout)",
+					condition)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Publish publica o caso de teste aplicando a política de retentativas. Retorna
+// a última resposta ou erro e o número de tentativas realizadas. onRetry, se
+// informado, é chamado antes de cada espera. Com o contexto cancelado não há
+// novas tentativas e o erro traz o motivo do cancelamento
+func Publish(
+	ctx context.Context,
+	requester RequestItf,
+	testCase rabbix.TestCase,
+	policy RetryPolicy,
+	onRetry func(attempt int, reason string, wait time.Duration),
+) (*http.Response, int, error) {
+	attempts := max(policy.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		resp, err := policy.attempt(ctx, requester, testCase)
+		if err != nil && ctx.Err() != nil {
+			if cause := context.Cause(ctx); !errors.Is(err, cause) {
+				err = fmt.Errorf("%w: %w", cause, err)
+			}
+
+			return resp, attempt, err
+		}
+
+		reason, retry := policy.retryable(resp, err)
+		if !retry || attempt >= attempts {
+			return resp, attempt, err
+		}
+
+		// A resposta descartada precisa ser fechada antes da nova tentativa
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		if err := policy.wait(ctx, attempt, reason, onRetry); err != nil {
+			return nil, attempt, err
+		}
+	}
+}
+
+// Call executa uma chamada request/reply aplicando a mesma política de Publish.
+// Cada tentativa publica uma nova requisição e tem como limite o Timeout da
+// política somado ao tempo de espera da resposta. Resposta não recebida
+// (ErrReplyTimeout) não é repetida, já que a requisição pode ter sido processada
+func Call(
+	ctx context.Context,
+	requester RequestItf,
+	testCase rabbix.TestCase,
+	options CallOptions,
+	policy RetryPolicy,
+	onRetry func(attempt int, reason string, wait time.Duration),
+) (Reply, int, error) {
+	attempts := max(policy.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		reply, err := policy.call(ctx, requester, testCase, options)
+		if err != nil && ctx.Err() != nil {
+			if cause := context.Cause(ctx); !errors.Is(err, cause) {
+				err = fmt.Errorf("%w: %w", cause, err)
+			}
+
+			return reply, attempt, err
+		}
+
+		reason, retry := policy.retryable(nil, err)
+		if !retry || attempt >= attempts {
+			return reply, attempt, err
+		}
+
+		if err := policy.wait(ctx, attempt, reason, onRetry); err != nil {
+			return reply, attempt, err
+		}
+	}
+}
+
+// wait aguarda o backoff antes da próxima tentativa, interrompendo com o contexto
+func (p RetryPolicy) wait(
+	ctx context.Context,
+	attempt int,
+	reason string,
+	onRetry func(attempt int, reason string, wait time.Duration),
+) error {
+	wait := p.backoff(attempt)
+	if onRetry != nil {
+		onRetry(attempt, reason, wait)
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%w (última falha: %s)", context.Cause(ctx), reason)
+	case <-timer.C:
+		return nil
+	}
+}
+
+// attempt executa uma tentativa dentro do tempo limite. O corpo da resposta é
+// lido antes de liberar o contexto, que também interromperia a leitura
+func (p RetryPolicy) attempt(
+	ctx context.Context,
+	requester RequestItf,
+	testCase rabbix.TestCase,
+) (*http.Response, error) {
+	if p.Timeout <= 0 {
+		return requester.Request(ctx, testCase)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	resp, err := requester.Request(attemptCtx, testCase)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			err = fmt.Errorf("tempo limite de %v esgotado: %w", p.Timeout, err)
+		}
+
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler resposta: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
+
+// call executa uma chamada request/reply dentro do tempo limite da tentativa
+func (p RetryPolicy) call(
+	ctx context.Context,
+	requester RequestItf,
+	testCase rabbix.TestCase,
+	options CallOptions,
+) (Reply, error) {
+	if p.Timeout <= 0 {
+		return requester.Call(ctx, testCase, options)
+	}
+
+	timeout := p.Timeout + options.Timeout
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	reply, err := requester.Call(attemptCtx, testCase, options)
+	if err != nil && errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		err = fmt.Errorf("tempo limite de %v esgotado: %w", timeout, err)
+	}
+
+	return reply, err
+}
+
+// retryable classifica a falha e indica se ela está em RetryOn
+func (p RetryPolicy) retryable(resp *http.Response, err error) (string, bool) {
+	var (
+		reason       string
+		publishError *PublishError
+	)
+
+	switch {
+	case err != nil && IsConfigError(err):
+		return "", false
+	case errors.As(err, &publishError):
+		reason = strconv.Itoa(publishError.StatusCode)
+	case err != nil && isTimeout(err):
+		reason = RetryTimeout
+	case err != nil && isNetwork(err):
+		reason = RetryNetwork
+	case err != nil:
+		return "", false
+	case resp != nil && (resp.StatusCode < 200 || resp.StatusCode >= 300):
+		reason = strconv.Itoa(resp.StatusCode)
+	default:
+		return "", false
+	}
+
+	for _, condition := range p.RetryOn {
+		condition = strings.ToLower(strings.TrimSpace(condition))
+
+		if condition == reason || (strings.HasSuffix(condition, "xx") && len(reason) == 3 && reason[0] == condition[0]) {
+			if err != nil {
+				return fmt.Sprintf("%s: %v", reason, err), true
+			}
+
+			return "status " + reason, true
+		}
+	}
+
+	return "", false
+}
+
+// backoff calcula a espera exponencial com jitter antes da próxima tentativa
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := float64(p.Backoff) * math.Pow(2, float64(attempt-1))
+	if p.MaxBackoff > 0 {
+		wait = math.Min(wait, float64(p.MaxBackoff))
+	}
+
+	if p.Jitter > 0 {
+		wait *= 1 - p.Jitter + rand.Float64()*2*p.Jitter
+	}
+
+	return time.Duration(wait)
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// isNetwork identifica falhas de conexão; erros permanentes de URL, como
+// esquema inválido, não são *net.OpError e ficam de fora
+func isNetwork(err error) bool {
+	var (
+		opErr   *net.OpError
+		amqpErr *amqp.Error
+	)
+
+	if errors.As(err, &amqpErr) {
+		return isTransientAMQP(amqpErr)
+	}
+
+	return errors.As(err, &opErr) ||
+		errors.Is(err, ErrNack) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// isTransientAMQP separa as falhas de conexão dos erros permanentes de canal,
+// como 404 (exchange inexistente) e 403 (acesso negado), que não adianta repetir.
+// Recover não serve para isso: a biblioteca o marca em todo erro de canal, 404 inclusive
+func isTransientAMQP(err *amqp.Error) bool {
+	switch err.Code {
+	case amqp.ConnectionForced, amqp.FrameError, amqp.ChannelError, amqp.ResourceError, amqp.InternalError:
+		return true
+	default:
+		return false
+	}
+}
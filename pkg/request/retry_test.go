@@ -0,0 +1,313 @@
+package request
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestRetryPolicyValidate(t *testing.T) {
+	valid := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, Jitter: 0.2, RetryOn: DefaultRetryOn}
+
+	tests := []struct {
+		name    string
+		change  func(p *RetryPolicy)
+		wantErr string
+	}{
+		{name: "válida", change: func(*RetryPolicy) {}},
+		{name: "classes e status", change: func(p *RetryPolicy) { p.RetryOn = []string{" 5XX ", "4xx", "409", "Network"} }},
+		{name: "sem tentativas", change: func(p *RetryPolicy) { p.MaxAttempts = 0 }, wantErr: "--max-attempts"},
+		{name: "timeout negativo", change: func(p *RetryPolicy) { p.Timeout = -1 }, wantErr: "--timeout"},
+		{name: "backoff negativo", change: func(p *RetryPolicy) { p.MaxBackoff = -1 }, wantErr: "--retry-backoff"},
+		{name: "jitter acima de 1", change: func(p *RetryPolicy) { p.Jitter = 1.5 }, wantErr: "--retry-jitter"},
+		{name: "status fora da faixa", change: func(p *RetryPolicy) { p.RetryOn = []string{"600"} }, wantErr: "'600'"},
+		{name: "classe inválida", change: func(p *RetryPolicy) { p.RetryOn = []string{"6xx"} }, wantErr: "'6xx'"},
+		{name: "condição desconhecida", change: func(p *RetryPolicy) { p.RetryOn = []string{"dns"} }, wantErr: "'dns'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := valid
+			tt.change(&policy)
+
+			err := policy.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate erro inesperado: %v", err)
+			}
+
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Validate erro = %v, esperado contendo %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	policy := RetryPolicy{RetryOn: []string{"network", "timeout", "429", "5xx"}}
+	opErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	tests := []struct {
+		name       string
+		status     int
+		err        error
+		wantRetry  bool
+		wantReason string
+	}{
+		{name: "sucesso", status: 200},
+		{name: "status listado", status: 429, wantRetry: true, wantReason: "status 429"},
+		{name: "classe 5xx", status: 503, wantRetry: true, wantReason: "status 503"},
+		{name: "status não listado", status: 404},
+		{name: "conexão recusada", err: fmt.Errorf("publicar: %w", opErr), wantRetry: true, wantReason: "network"},
+		{name: "conexão resetada", err: syscall.ECONNRESET, wantRetry: true, wantReason: "network"},
+		{name: "fim inesperado", err: io.ErrUnexpectedEOF, wantRetry: true, wantReason: "network"},
+		{name: "nack", err: ErrNack, wantRetry: true, wantReason: "network"},
+		{name: "prazo esgotado", err: context.DeadlineExceeded, wantRetry: true, wantReason: "timeout"},
+		{
+			name:       "conexão amqp encerrada",
+			err:        &amqp.Error{Code: amqp.ConnectionForced, Reason: "shutdown"},
+			wantRetry:  true,
+			wantReason: "network",
+		},
+		{name: "exchange inexistente", err: &amqp.Error{Code: amqp.NotFound, Recover: true}},
+		{name: "acesso negado", err: &amqp.Error{Code: amqp.AccessRefused, Recover: true}},
+		{name: "erro de configuração", err: fmt.Errorf("x: %w", ErrAuthNotConfigured)},
+		{name: "erro permanente", err: errors.New("esquema inválido")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status}
+			}
+
+			reason, retry := policy.retryable(resp, tt.err)
+			if retry != tt.wantRetry {
+				t.Fatalf("retryable = %v (%q), esperado %v", retry, reason, tt.wantRetry)
+			}
+
+			if !strings.HasPrefix(reason, tt.wantReason) {
+				t.Errorf("motivo = %q, esperado começando por %q", reason, tt.wantReason)
+			}
+		})
+	}
+
+	if _, retry := (RetryPolicy{RetryOn: []string{"429"}}).retryable(nil, opErr); retry {
+		t.Error("falha de rede sem 'network' em RetryOn não deveria ser repetida")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		min, max time.Duration
+	}{
+		{name: "primeira espera", policy: RetryPolicy{Backoff: 100 * time.Millisecond}, attempt: 1,
+			min: 100 * time.Millisecond, max: 100 * time.Millisecond},
+		{name: "dobra a cada tentativa", policy: RetryPolicy{Backoff: 100 * time.Millisecond}, attempt: 4,
+			min: 800 * time.Millisecond, max: 800 * time.Millisecond},
+		{name: "limitada pelo máximo", policy: RetryPolicy{Backoff: time.Second, MaxBackoff: 3 * time.Second},
+			attempt: 10, min: 3 * time.Second, max: 3 * time.Second},
+		{name: "jitter", policy: RetryPolicy{Backoff: time.Second, Jitter: 0.2}, attempt: 1,
+			min: 800 * time.Millisecond, max: 1200 * time.Millisecond},
+		{name: "jitter sobre o máximo", policy: RetryPolicy{Backoff: time.Second, MaxBackoff: time.Second, Jitter: 0.5},
+			attempt: 5, min: 500 * time.Millisecond, max: 1500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 100 {
+				if wait := tt.policy.backoff(tt.attempt); wait < tt.min || wait > tt.max {
+					t.Fatalf("backoff(%d) = %v, esperado entre %v e %v", tt.attempt, wait, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+// scriptedRequester devolve os status ou erros na ordem informada
+type scriptedRequester struct {
+	RequestItf
+
+	results []any
+	calls   int
+}
+
+func (s *scriptedRequester) Request(context.Context, rabbix.TestCase) (*http.Response, error) {
+	result := s.results[min(s.calls, len(s.results)-1)]
+	s.calls++
+
+	if err, ok := result.(error); ok {
+		return nil, err
+	}
+
+	return &http.Response{StatusCode: result.(int), Body: io.NopCloser(strings.NewReader("{}"))}, nil
+}
+
+// errHang faz a chamada do scriptedRequester esperar até o fim do contexto
+var errHang = errors.New("sem resposta")
+
+// Call segue o mesmo roteiro: status 2xx entrega a resposta, os demais são
+// publicações recusadas
+func (s *scriptedRequester) Call(ctx context.Context, _ rabbix.TestCase, _ CallOptions) (Reply, error) {
+	result := s.results[min(s.calls, len(s.results)-1)]
+	s.calls++
+
+	switch result := result.(type) {
+	case error:
+		if errors.Is(result, errHang) {
+			<-ctx.Done()
+			return Reply{}, ctx.Err()
+		}
+
+		return Reply{}, result
+	case int:
+		if result < 200 || result >= 300 {
+			return Reply{}, &PublishError{StatusCode: result, Body: "indisponível"}
+		}
+	}
+
+	return Reply{CorrelationID: fmt.Sprintf("tentativa-%d", s.calls), Routed: true}, nil
+}
+
+func TestPublish(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, RetryOn: DefaultRetryOn}
+
+	tests := []struct {
+		name         string
+		results      []any
+		wantStatus   int
+		wantAttempts int
+		wantRetries  int
+		wantErr      bool
+	}{
+		{name: "sucesso de primeira", results: []any{200}, wantStatus: 200, wantAttempts: 1},
+		{name: "sucesso após falhas", results: []any{503, syscall.ECONNRESET, 200}, wantStatus: 200,
+			wantAttempts: 3, wantRetries: 2},
+		{name: "esgota as tentativas", results: []any{503}, wantStatus: 503, wantAttempts: 3, wantRetries: 2},
+		{name: "falha permanente", results: []any{404}, wantStatus: 404, wantAttempts: 1},
+		{name: "erro não repetido", results: []any{errors.New("x")}, wantAttempts: 1, wantErr: true},
+		{name: "nack do broker", results: []any{ErrNack, 200}, wantStatus: 200, wantAttempts: 2, wantRetries: 1},
+		{name: "conexão amqp encerrada", results: []any{&amqp.Error{Code: amqp.ConnectionForced}, 200}, wantStatus: 200,
+			wantAttempts: 2, wantRetries: 1},
+		// O canal fechado com 404 também vem com Recover, mas repetir não cria o exchange
+		{name: "exchange inexistente", results: []any{&amqp.Error{Code: amqp.NotFound, Recover: true}, 200},
+			wantAttempts: 1, wantErr: true},
+		{name: "sem credenciais", results: []any{ErrAuthNotConfigured, 200}, wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requester := &scriptedRequester{results: tt.results}
+			retries := 0
+
+			resp, attempts, err := Publish(context.Background(), requester, rabbix.TestCase{}, policy,
+				func(int, string, time.Duration) { retries++ })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Publish erro = %v, esperado erro: %v", err, tt.wantErr)
+			}
+
+			if resp != nil && resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, esperado %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			if attempts != tt.wantAttempts || requester.calls != tt.wantAttempts || retries != tt.wantRetries {
+				t.Errorf("tentativas = %d (chamadas %d, retentativas %d), esperado %d e %d retentativas",
+					attempts, requester.calls, retries, tt.wantAttempts, tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestPublishCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cause := errors.New("interrompido")
+
+	policy := RetryPolicy{MaxAttempts: 5, Backoff: time.Hour, RetryOn: DefaultRetryOn}
+	requester := &scriptedRequester{results: []any{503}}
+
+	_, attempts, err := Publish(ctx, requester, rabbix.TestCase{}, policy,
+		func(int, string, time.Duration) { cancel(cause) })
+	if !errors.Is(err, cause) || attempts != 1 {
+		t.Errorf("Publish cancelado = %d tentativa(s), erro %v; esperado 1 e %v", attempts, err, cause)
+	}
+}
+
+func TestCall(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, RetryOn: DefaultRetryOn}
+
+	tests := []struct {
+		name         string
+		results      []any
+		wantAttempts int
+		wantErr      string
+	}{
+		{name: "resposta de primeira", results: []any{200}, wantAttempts: 1},
+		{name: "publicação recusada e repetida", results: []any{503, syscall.ECONNREFUSED, 200}, wantAttempts: 3},
+		{name: "status não listado", results: []any{404}, wantAttempts: 1, wantErr: "status 404"},
+		{name: "esgota as tentativas", results: []any{502}, wantAttempts: 3, wantErr: "status 502"},
+		// A requisição pode ter sido processada, então a falta de resposta não é repetida
+		{name: "resposta não recebida", results: []any{ErrReplyTimeout, 200}, wantAttempts: 1,
+			wantErr: ErrReplyTimeout.Error()},
+		{name: "erro de configuração", results: []any{ErrAuthNotConfigured}, wantAttempts: 1, wantErr: "autenticação"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requester := &scriptedRequester{results: tt.results}
+
+			reply, attempts, err := Call(context.Background(), requester, rabbix.TestCase{}, CallOptions{}, policy, nil)
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("Call erro inesperado: %v", err)
+			case tt.wantErr == "" && reply.CorrelationID != fmt.Sprintf("tentativa-%d", tt.wantAttempts):
+				t.Errorf("resposta = %+v, esperado a da tentativa %d", reply, tt.wantAttempts)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Call erro = %v, esperado contendo %q", err, tt.wantErr)
+			}
+
+			if attempts != tt.wantAttempts || requester.calls != tt.wantAttempts {
+				t.Errorf("tentativas = %d (chamadas %d), esperado %d", attempts, requester.calls, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestCallAttemptTimeout(t *testing.T) {
+	// O limite da tentativa soma o --timeout e a espera da resposta
+	policy := RetryPolicy{Timeout: 20 * time.Millisecond, MaxAttempts: 2, RetryOn: DefaultRetryOn}
+	requester := &scriptedRequester{results: []any{errHang, 200}}
+	options := CallOptions{Timeout: 30 * time.Millisecond}
+
+	start := time.Now()
+
+	_, attempts, err := Call(context.Background(), requester, rabbix.TestCase{}, options, policy, nil)
+	if err != nil || attempts != 2 {
+		t.Fatalf("Call = %d tentativa(s), erro %v; esperado sucesso na segunda", attempts, err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > time.Second {
+		t.Errorf("primeira tentativa encerrada após %v, esperado cerca de 50ms", elapsed)
+	}
+
+	requester = &scriptedRequester{results: []any{errHang}}
+	policy.MaxAttempts = 1
+
+	if _, _, err := Call(context.Background(), requester, rabbix.TestCase{}, options, policy, nil); err == nil ||
+		!strings.Contains(err.Error(), "tempo limite de 50ms esgotado") {
+		t.Errorf("Call erro = %v, esperado tempo limite da tentativa", err)
+	}
+}
@@ -0,0 +1,102 @@
+package request
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+)
+
+// ErrReplyTimeout indica que a resposta não chegou dentro do tempo limite
+var ErrReplyTimeout = errors.New("tempo limite esgotado aguardando a resposta")
+
+// CallOptions define a fila e o tempo de espera de uma chamada request/reply
+type CallOptions struct {
+	// ReplyQueue é uma fila existente para as respostas; vazia cria uma fila temporária
+	ReplyQueue string
+	Timeout    time.Duration
+	// AllowUnroutable aguarda a resposta mesmo quando a requisição não foi roteada
+	// para nenhuma fila, em vez de falhar logo após a publicação
+	AllowUnroutable bool
+}
+
+// Reply é a resposta de uma chamada request/reply
+type Reply struct {
+	CorrelationID string
+	ReplyQueue    string
+	Latency       time.Duration
+	Message       Message
+	// Routed indica se a requisição foi roteada para alguma fila
+	Routed bool
+}
+
+// PublishError indica que a publicação de uma chamada request/reply foi recusada
+// pela API de gerenciamento; o status permite classificar a falha nas retentativas
+type PublishError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("publicação falhou com status %d: %s", e.StatusCode, e.Body)
+}
+
+// prepareCall define reply_to e gera o correlation_id, sem alterar o caso de teste original
+func prepareCall(testCase rabbix.TestCase, replyQueue string) (rabbix.TestCase, string) {
+	properties := rabbix.Properties{}
+	if testCase.Properties != nil {
+		properties = *testCase.Properties
+	}
+
+	if properties.CorrelationID == "" {
+		properties.CorrelationID = newID()
+	}
+
+	properties.ReplyTo = replyQueue
+	testCase.Properties = &properties
+
+	return testCase, properties.CorrelationID
+}
+
+// checkPublish valida a resposta da publicação de uma chamada request/reply e
+// indica se a requisição foi roteada
+func checkPublish(resp *http.Response, allowUnroutable bool) (bool, error) {
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, fmt.Errorf("erro ao ler resposta: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, &PublishError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	result, err := ParseResult(body)
+	if err != nil {
+		return false, err
+	}
+
+	if !result.Routed && !allowUnroutable {
+		return false, errors.New("mensagem não roteada para nenhuma fila (verifique route key e exchange)")
+	}
+
+	return result.Routed, nil
+}
+
+// newID gera um identificador aleatório no formato UUID v4
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,186 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+)
+
+// fakeSettings aponta a API de gerenciamento para o servidor de teste
+type fakeSettings map[string]string
+
+func (f fakeSettings) LoadSettings() map[string]string { return f }
+func (f fakeSettings) SaveSettings(map[string]string)  {}
+func (f fakeSettings) GetBaseDir() string              { return "" }
+
+// fakeBroker emula os endpoints da API de gerenciamento usados pelo HTTP.Call.
+// Cada requisição publicada recebe uma resposta com o mesmo correlation_id na
+// fila indicada em reply_to, depois das mensagens que já estavam lá
+type fakeBroker struct {
+	mutex  sync.Mutex
+	queues map[string][]Message
+	routed bool
+}
+
+func newFakeBroker(t *testing.T) (*fakeBroker, *HTTP) {
+	broker := &fakeBroker{queues: map[string][]Message{}, routed: true}
+
+	server := httptest.NewServer(broker)
+	t.Cleanup(server.Close)
+
+	return broker, NewHTTP(fakeSettings{"host": server.URL, "auth": "dGVzdGU6dGVzdGU="})
+}
+
+func (b *fakeBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	parts := strings.Split(r.URL.EscapedPath(), "/")
+	if len(parts) < 5 {
+		http.NotFound(w, r)
+		return
+	}
+
+	name, _ := url.PathUnescape(parts[4])
+
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	switch {
+	case parts[2] == "exchanges" && name == rabbix.DefaultExchange:
+		queue, _ := body["routing_key"].(string)
+		b.queues[queue] = append(b.queues[queue], messageOf(body))
+		_, _ = w.Write([]byte(`{"routed":true}`))
+	case parts[2] == "exchanges":
+		request := messageOf(body)
+		if replyTo, _ := request.Properties["reply_to"].(string); b.routed && replyTo != "" {
+			b.queues[replyTo] = append(b.queues[replyTo], Message{
+				Properties: map[string]any{"correlation_id": request.Properties["correlation_id"]},
+				Payload:    `{"status":"ok"}`,
+			})
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]bool{"routed": b.routed})
+	case parts[2] == "queues" && len(parts) == 6 && parts[5] == "get":
+		count := min(int(body["count"].(float64)), len(b.queues[name]))
+		messages := append([]Message{}, b.queues[name][:count]...)
+
+		if body["ackmode"] == "ack_requeue_false" {
+			b.queues[name] = b.queues[name][count:]
+		}
+
+		_ = json.NewEncoder(w).Encode(messages)
+	case parts[2] == "queues" && r.Method == http.MethodDelete:
+		delete(b.queues, name)
+	case parts[2] == "queues":
+		b.queues[name] = nil
+	}
+}
+
+func messageOf(body map[string]any) Message {
+	properties, _ := body["properties"].(map[string]any)
+	payload, _ := body["payload"].(string)
+
+	return Message{Properties: properties, Payload: payload, PayloadEncoding: "string"}
+}
+
+func (b *fakeBroker) correlationIDs(queue string) []any {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	ids := []any{}
+	for _, message := range b.queues[queue] {
+		ids = append(ids, message.Properties["correlation_id"])
+	}
+
+	return ids
+}
+
+func TestHTTPCallConsumesOnlyItsReply(t *testing.T) {
+	broker, transport := newFakeBroker(t)
+
+	// Respostas de outras chamadas aguardam na fila compartilhada
+	broker.queues["respostas"] = []Message{
+		{Properties: map[string]any{"correlation_id": "outra-1"}, Payload: "a", PayloadEncoding: "string"},
+		{Properties: map[string]any{"correlation_id": "outra-2"}, Payload: "b", PayloadEncoding: "string"},
+	}
+
+	tc := rabbix.TestCase{Name: "consulta", Exchange: "consultas", RouteKey: "precos"}
+	options := CallOptions{ReplyQueue: "respostas", Timeout: 2 * time.Second}
+
+	reply, err := transport.Call(context.Background(), tc, options)
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+
+	if reply.Message.Properties["correlation_id"] != reply.CorrelationID || reply.Message.Payload != `{"status":"ok"}` {
+		t.Errorf("resposta = %+v, esperado a da chamada %s", reply.Message, reply.CorrelationID)
+	}
+
+	if !reply.Routed || reply.ReplyQueue != "respostas" {
+		t.Errorf("Call = roteada %v, fila %q", reply.Routed, reply.ReplyQueue)
+	}
+
+	// A resposta foi consumida e as das outras chamadas continuam na fila
+	if ids := broker.correlationIDs("respostas"); len(ids) != 2 || ids[0] != "outra-1" || ids[1] != "outra-2" {
+		t.Errorf("fila de resposta após a chamada = %v, esperado [outra-1 outra-2]", ids)
+	}
+
+	if _, err := transport.Call(context.Background(), tc, options); err != nil {
+		t.Fatalf("segunda Call: %v", err)
+	}
+
+	if ids := broker.correlationIDs("respostas"); len(ids) != 2 {
+		t.Errorf("fila de resposta após a segunda chamada = %v, esperado apenas as outras respostas", ids)
+	}
+}
+
+func TestHTTPCallTemporaryQueue(t *testing.T) {
+	broker, transport := newFakeBroker(t)
+
+	tc := rabbix.TestCase{Name: "consulta", Exchange: "consultas", RouteKey: "precos"}
+
+	reply, err := transport.Call(context.Background(), tc, CallOptions{Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+
+	if !strings.HasPrefix(reply.ReplyQueue, "rabbix.reply.") {
+		t.Errorf("fila temporária = %q", reply.ReplyQueue)
+	}
+
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	if _, ok := broker.queues[reply.ReplyQueue]; ok {
+		t.Error("a fila temporária deveria ser removida ao fim da chamada")
+	}
+}
+
+func TestHTTPCallUnroutable(t *testing.T) {
+	broker, transport := newFakeBroker(t)
+	broker.routed = false
+
+	tc := rabbix.TestCase{Name: "consulta", Exchange: "consultas", RouteKey: "sem-fila"}
+
+	if _, err := transport.Call(context.Background(), tc, CallOptions{Timeout: time.Second}); err == nil ||
+		!strings.Contains(err.Error(), "não roteada") {
+		t.Errorf("Call erro = %v, esperado requisição não roteada", err)
+	}
+
+	// Com AllowUnroutable a chamada aguarda a resposta até o tempo limite
+	reply, err := transport.Call(context.Background(), tc,
+		CallOptions{Timeout: 300 * time.Millisecond, AllowUnroutable: true})
+	if !errors.Is(err, ErrReplyTimeout) || reply.Routed {
+		t.Errorf("Call = roteada %v, erro %v; esperado %v", reply.Routed, err, ErrReplyTimeout)
+	}
+}
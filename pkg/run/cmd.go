@@ -1,9 +1,12 @@
 package run
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -11,9 +14,16 @@ import (
 	"time"
 
 	"github.com/maxwelbm/rabbix/pkg/cache"
+	"github.com/maxwelbm/rabbix/pkg/dataset"
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/expect"
+	"github.com/maxwelbm/rabbix/pkg/interrupt"
+	"github.com/maxwelbm/rabbix/pkg/mock"
+	"github.com/maxwelbm/rabbix/pkg/output"
 	"github.com/maxwelbm/rabbix/pkg/rabbix"
 	"github.com/maxwelbm/rabbix/pkg/request"
 	"github.com/maxwelbm/rabbix/pkg/sett"
+	"github.com/maxwelbm/rabbix/pkg/tmpl"
 	"github.com/spf13/cobra"
 )
 
@@ -37,16 +47,50 @@ func New(
 
 func (r *Run) CmdRun() *cobra.Command {
 	var (
-		quantity int
-		mockSpec string
+		quantity        int
+		mockSpec        string
+		seed            int64
+		exchange        string
+		vhost           string
+		transport       string
+		properties      []string
+		allowUnroutable bool
+		rpc             bool
+		callOptions     request.CallOptions
+		retry           request.RetryPolicy
+		overallTimeout  time.Duration
+		vars            []string
+		dataPath        string
 	)
 
 	var cmd = &cobra.Command{
 		Use:   "run [test-name]",
 		Short: "Executa um caso de teste específico",
 		Long: `Executa um caso de teste específico salvamento previamente.
-Exemplo: rabbix run meu-teste`,
-		Args: cobra.ExactArgs(1),
+Valores do json_pool e dos headers aceitam placeholders avaliados a cada iteração:
+  {{uuid}}, {{int 1 100}}, {{float 0 10}}, {{bool}}, {{string 8}}, {{seq}},
+  {{now "2006-01-02"}}, {{now "unix"}}, {{env "TENANT"}}, {{vars.nome}}
+O bloco 'capture' do teste guarda valores da mensagem publicada (from: payload ou headers)
+ou da resposta do --rpc (from: reply), disponíveis nas iterações seguintes como {{vars.nome}}.
+Com --data cada linha de um arquivo CSV ou JSON Lines gera uma publicação. As colunas
+são caminhos do json_pool (ex: order.id, order.qty:int), headers (headers.tenant) ou a
+route key (route_key); os tipos aceitos são string, int, float, bool e json.
+O --mock aceita caminhos aninhados e os tipos int, float, string, bool, time, uuid,
+name, email, cpf, cnpj, phone, int(min,max), float(min,max), string(n), enum(A|B) e regex(padrão).
+Quando o caso de teste define um bloco 'expect', a fila indicada é observada após cada
+publicação e as asserções de payload, headers e quantidade de mensagens são avaliadas.
+Ctrl+C interrompe as próximas iterações, aguarda a publicação em andamento e exibe o
+resumo parcial; --overall-timeout limita o tempo total da execução.
+Exemplos:
+  rabbix run meu-teste
+  rabbix run meu-teste -n 10 --seed 42 --mock 'order.items[0].sku:uuid,qty:int(1,10),status:enum(A|B|C)'
+  rabbix run consulta-preco --rpc --reply-timeout 5s
+  rabbix run meu-teste --var tenant=acme
+  rabbix run pedido-criado --data pedidos.csv
+  rabbix run meu-teste -n 1000 --timeout 2s --overall-timeout 1m`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			// Sincroniza cache antes de fornecer sugestões
 			r.Cache.SyncCacheWithFileSystem()
@@ -56,7 +100,7 @@ Exemplo: rabbix run meu-teste`,
 
 			return cachedTests, cobra.ShellCompDirectiveNoFileComp
 		},
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			testName := args[0]
 
 			// Carrega configuração para obter diretório de saída
@@ -71,145 +115,505 @@ Exemplo: rabbix run meu-teste`,
 			testPath := filepath.Join(outputDir, testName+".json")
 			data, err := os.ReadFile(testPath)
 			if err != nil {
-				fmt.Printf("❌ Erro: Teste '%s' não encontrado em %s\n", testName, testPath)
-				fmt.Println("💡 Use 'rabbix list' para ver os testes disponíveis")
-				return
+				output.Println("💡 Use 'rabbix list' para ver os testes disponíveis")
+				return exitcode.New(exitcode.NotFound, "teste '%s' não encontrado em %s", testName, testPath)
 			}
 
 			var tc rabbix.TestCase
 			if err := json.Unmarshal(data, &tc); err != nil {
-				fmt.Printf("❌ Erro ao carregar JSON do teste '%s': %v\n", testName, err)
-				return
+				return exitcode.New(exitcode.Config, "erro ao carregar JSON do teste '%s': %w", testName, err)
+			}
+
+			if exchange != "" {
+				tc.Exchange = exchange
+			}
+
+			if vhost != "" {
+				tc.Vhost = vhost
+			}
+
+			if transport != "" {
+				tc.Transport = transport
+			}
+
+			// Sobrescreve as properties do arquivo com as informadas na execução
+			for _, pair := range properties {
+				key, value, ok := strings.Cut(pair, "=")
+				if !ok {
+					return exitcode.New(exitcode.Config, "property inválida '%s' (esperado 'chave=valor')", pair)
+				}
+
+				if tc.Properties == nil {
+					tc.Properties = &rabbix.Properties{}
+				}
+
+				if err := tc.Properties.Set(key, value); err != nil {
+					return exitcode.New(exitcode.Config, "property inválida '%s': %w", pair, err)
+				}
+			}
+
+			if err := retry.Validate(); err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
+			callOptions.AllowUnroutable = allowUnroutable
+
+			if overallTimeout < 0 {
+				return exitcode.New(exitcode.Config, "--overall-timeout não pode ser negativo")
+			}
+
+			mockFields, warnings, err := mock.Parse(mockSpec)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
+			// O --mock preenche o json_pool; com payload os campos seriam descartados
+			if len(mockFields) > 0 && tc.Payload != nil {
+				return exitcode.New(exitcode.Config,
+					"--mock exige um teste com json_pool, o teste '%s' usa 'payload'", tc.Name)
 			}
 
 			// Garante que JSONPool exista
-			if tc.JSONPool == nil {
+			if tc.JSONPool == nil && tc.Payload == nil {
 				tc.JSONPool = map[string]any{}
 			}
 
-			// Parser do mockSpec -> []string de "campo:tipo"
-			var mockPairs []string
-			if strings.TrimSpace(mockSpec) != "" {
-				// permite JSON array ou lista separada por vírgula
-				trim := strings.TrimSpace(mockSpec)
-				if strings.HasPrefix(trim, "[") {
-					if err := json.Unmarshal([]byte(trim), &mockPairs); err != nil {
-						fmt.Printf("⚠️  Não foi possível interpretar --mock como JSON array: %v\n", err)
-						// tenta fallback por vírgulas removendo colchetes
-						trim = strings.Trim(trim, "[]")
-						if trim != "" {
-							mockPairs = strings.Split(trim, ",")
-						}
+			for _, warning := range warnings {
+				output.Printf("⚠️  %s\n", warning)
+			}
+
+			if quantity <= 0 {
+				quantity = 1
+			}
+
+			var rows []dataset.Row
+
+			if dataPath != "" {
+				if cmd.Flags().Changed("quantity") {
+					return exitcode.New(exitcode.Config, "use --data ou --quantity, não os dois")
+				}
+
+				if rows, err = dataset.Load(dataPath); err != nil {
+					if errors.Is(err, fs.ErrNotExist) {
+						return exitcode.New(exitcode.NotFound, "arquivo de dados '%s' não encontrado", dataPath)
 					}
-				} else {
-					mockPairs = strings.Split(trim, ",")
+
+					return exitcode.New(exitcode.Config, "erro ao ler '%s': %w", dataPath, err)
 				}
-				// limpeza de espaços e aspas
-				for i := range mockPairs {
-					mockPairs[i] = strings.Trim(mockPairs[i], " \"\n\t")
+
+				if len(rows) == 0 {
+					return exitcode.New(exitcode.Config, "o arquivo de dados '%s' não possui linhas", dataPath)
 				}
+
+				quantity = len(rows)
 			}
 
-			if quantity <= 0 {
-				quantity = 1
+			output.Printf("🚀 Executando teste: %s\n", tc.Name)
+			output.Printf("📤 Route Key: %s\n", tc.RouteKey)
+
+			if tc.Exchange != "" {
+				output.Printf("🔀 Exchange: %s\n", tc.Exchange)
 			}
 
-			fmt.Printf("🚀 Executando teste: %s\n", tc.Name)
-			fmt.Printf("📤 Route Key: %s\n", tc.RouteKey)
+			if tc.Vhost != "" {
+				output.Printf("🏠 Vhost: %s\n", tc.Vhost)
+			}
 
-			if quantity > 1 {
-				fmt.Printf("🔁 Quantidade: %d\n", quantity)
+			if len(rows) > 0 {
+				output.Printf("📄 Dados: %s (%d linha(s))\n", dataPath, len(rows))
+			} else if quantity > 1 {
+				output.Printf("🔁 Quantidade: %d\n", quantity)
 			}
-			if len(mockPairs) > 0 {
-				fmt.Printf("🧪 Mock: %v\n", mockPairs)
+			if len(mockFields) > 0 {
+				output.Printf("🧪 Mock: %v\n", mockFields)
 			}
 
-			for i := 1; i <= quantity; i++ {
+			if tc.Expect != nil {
+				if err := tc.Expect.Validate(); err != nil {
+					return exitcode.New(exitcode.Config, "bloco expect inválido: %w", err)
+				}
+
+				output.Printf("🔎 Expect: fila %s\n", tc.Expect.Queue)
+			}
+
+			if len(tc.Capture) > 0 {
+				if err := rabbix.ValidateCaptures(tc.Capture); err != nil {
+					return exitcode.New(exitcode.Config, "bloco capture inválido: %w", err)
+				}
+
+				if rabbix.UsesReply(tc.Capture) && !rpc {
+					return exitcode.New(exitcode.Config, "o bloco capture usa from: reply, que exige --rpc")
+				}
+
+				output.Printf("📌 Capture: %s\n", strings.Join(rabbix.CaptureNames(tc.Capture), ", "))
+			}
+
+			engine := tmpl.New()
+			if err := engine.SetVarPairs(vars); err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
+			// Com --seed os valores gerados se repetem entre execuções
+			if !cmd.Flags().Changed("seed") {
+				seed = time.Now().UnixNano()
+			}
+
+			rng := rand.New(rand.NewSource(seed))
+			engine.Seed(seed)
+
+			// Mantém a conexão do transporte aberta durante todas as iterações
+			defer func() {
+				if err := r.request.Close(); err != nil {
+					output.Printf("❌ Erro ao encerrar conexão: %v\n", err)
+				}
+			}()
+
+			// Ctrl+C interrompe as próximas iterações; o tempo total cancela também a atual
+			scope := interrupt.New(cmd.Context(), overallTimeout)
+			defer scope.Close()
+
+			summary := Summary{Test: tc.Name, RouteKey: tc.RouteKey, Quantity: quantity, Data: dataPath}
+
+			for i := 1; i <= quantity && !scope.Stopped(); i++ {
+				it := Iteration{Iteration: i}
+				start := time.Now()
+
+				base := tc
+
+				// Com --data a iteração publica os valores da linha correspondente
+				if len(rows) > 0 {
+					row := rows[i-1]
+					it.Row = row.Line
+
+					if base, err = row.Apply(tc); err != nil {
+						output.Printf("❌ [%d/%d] %v\n", i, quantity, err)
+						summary.add(it.fail(err.Error(), start))
+
+						continue
+					}
+				}
+
+				// Avalia os placeholders do arquivo a cada iteração, sempre a partir do original
+				msg, err := engine.RenderTestCase(base)
+				if err != nil {
+					output.Printf("❌ [%d/%d] Erro ao avaliar placeholders: %v\n", i, quantity, err)
+					summary.add(it.fail(err.Error(), start))
+
+					continue
+				}
+
 				// aplica mocks por iteração
-				if len(mockPairs) > 0 {
-					seed := time.Now().UnixNano() + int64(i)
-					rng := rand.New(rand.NewSource(seed))
-					for _, pair := range mockPairs {
-						if pair == "" {
-							continue
-						}
-						parts := strings.SplitN(pair, ":", 2)
-						if len(parts) != 2 {
-							fmt.Printf("⚠️  Par inválido em --mock: '%s' (esperado 'campo:tipo')\n", pair)
-							continue
-						}
-						field := strings.TrimSpace(parts[0])
-						typeName := strings.ToLower(strings.TrimSpace(parts[1]))
-						var value any
-						switch typeName {
-						case "int":
-							value = rng.Intn(1000000)
-						case "float", "float64":
-							value = rng.Float64() * 100000
-						case "string":
-							value = randomString(12, rng)
-						case "time", "datetime", "date":
-							value = time.Now().Format(time.RFC3339)
-						case "bool", "boolean":
-							value = rng.Intn(2) == 0
-						default:
-							fmt.Printf("⚠️  Tipo desconhecido '%s' para campo '%s'. Usando string.\n", typeName, field)
-							value = randomString(8, rng)
-						}
-						// aplica no JSONPool
-						tc.JSONPool[field] = value
+				if err := mock.Apply(msg.JSONPool, mockFields, rng); err != nil {
+					output.Printf("❌ [%d/%d] Erro ao aplicar --mock: %v\n", i, quantity, err)
+					summary.add(it.fail(err.Error(), start))
+
+					continue
+				}
+
+				if rpc {
+					if msg.Expect != nil && i == 1 {
+						output.Println("⚠️  O bloco expect é ignorado no modo --rpc")
 					}
+
+					success, err := r.call(scope.Work, i, quantity, msg, callOptions, retry, &it)
+					if err != nil {
+						return exitcode.Wrap(exitcode.Config, err)
+					}
+
+					it.Success = success
+
+					if it.Success && len(msg.Capture) > 0 {
+						sources := tmpl.SourcesOf(msg).WithReply([]byte(it.Response))
+						it.Success = r.capture(i, quantity, engine, msg.Capture, sources, &it)
+					}
+
+					it.DurationMs = output.Milliseconds(time.Since(start))
+					summary.add(it)
+
+					continue
+				}
+
+				// Com expect.purge, mensagens antigas da fila não contam para esta publicação
+				if err := expect.Prepare(scope.Work, r.request, msg); err != nil {
+					output.Printf("❌ [%d/%d] %v\n", i, quantity, err)
+					summary.add(it.fail(err.Error(), start))
+
+					continue
 				}
-				// Usa a função reutilizável PublishMessage
-				resp, err := r.request.Request(tc)
+
+				// Usa a função reutilizável PublishMessage, repetindo falhas transitórias
+				resp, attempts, err := request.Publish(scope.Work, r.request, msg, retry,
+					func(attempt int, reason string, wait time.Duration) {
+						output.Printf("🔁 [%d/%d] Tentativa %d/%d falhou (%s), nova tentativa em %v\n",
+							i, quantity, attempt, retry.MaxAttempts, reason, wait.Round(time.Millisecond))
+					})
+				it.Attempts = attempts
+
 				if err != nil {
-					fmt.Printf("❌ [%d/%d] Erro ao enviar mensagem: %v\n", i, quantity, err)
+					// Erros de configuração se repetiriam em todas as iterações
+					if request.IsConfigError(err) {
+						return exitcode.Wrap(exitcode.Config, err)
+					}
+
+					output.Printf("❌ [%d/%d] Erro ao enviar mensagem: %v\n", i, quantity, err)
+					summary.add(it.fail(err.Error(), start))
+
 					continue
 				}
 
-				func() {
+				published := func() bool {
 					defer func() {
 						if err := resp.Body.Close(); err != nil {
-							fmt.Printf("❌ Erro ao fechar corpo da resposta: %v\n", err)
+							output.Printf("❌ Erro ao fechar corpo da resposta: %v\n", err)
 						}
 					}()
 
+					it.Status = resp.StatusCode
+
 					// Lê a resposta
 					body, err := io.ReadAll(resp.Body)
 					if err != nil {
-						fmt.Printf("❌ [%d/%d] Erro ao ler resposta: %v\n", i, quantity, err)
-						return
+						output.Printf("❌ [%d/%d] Erro ao ler resposta: %v\n", i, quantity, err)
+						it.Error = err.Error()
+
+						return false
 					}
 
+					it.Response = string(body)
+
+					var ok bool
+
 					// Exibe o resultado
 					if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-						fmt.Printf("✅ [%d/%d] Mensagem enviada com sucesso! (Status: %d)\n", i, quantity, resp.StatusCode)
+						result, err := request.ParseResult(body)
+						it.Routed = result.Routed
+
+						switch {
+						case err != nil:
+							it.Error = err.Error()
+							output.Printf("⚠️  [%d/%d] %v\n", i, quantity, err)
+						case result.Routed:
+							ok = true
+							output.Printf("✅ [%d/%d] Mensagem enviada com sucesso! (Status: %d)\n", i, quantity, resp.StatusCode)
+						case allowUnroutable:
+							ok = true
+							output.Printf("⚠️  [%d/%d] Mensagem enviada, mas não roteada para nenhuma fila\n", i, quantity)
+						default:
+							it.Error = "mensagem não roteada para nenhuma fila"
+							output.Printf("❌ [%d/%d] Mensagem não roteada para nenhuma fila (verifique route key e exchange)\n",
+								i, quantity)
+						}
 					} else {
-						fmt.Printf("⚠️  [%d/%d] Resposta com status %d\n", i, quantity, resp.StatusCode)
+						it.Error = fmt.Sprintf("status HTTP %d", resp.StatusCode)
+						output.Printf("⚠️  [%d/%d] Resposta com status %d\n", i, quantity, resp.StatusCode)
 					}
 
-					fmt.Printf("📥 [%d/%d] Resposta do RabbitMQ:\n%s\n", i, quantity, string(body))
+					output.Printf("📥 [%d/%d] Resposta do RabbitMQ:\n%s\n", i, quantity, string(body))
+
+					return ok
 				}()
+
+				it.DurationMs = output.Milliseconds(time.Since(start))
+				it.Success = published
+
+				// Captura as variáveis da mensagem publicada para as próximas iterações
+				if published && len(msg.Capture) > 0 {
+					it.Success = r.capture(i, quantity, engine, msg.Capture, tmpl.SourcesOf(msg), &it)
+				}
+
+				// Só verifica o resultado esperado das mensagens publicadas
+				if it.Success && msg.Expect != nil {
+					it.Success = r.expect(scope.Work, i, quantity, engine, msg, &it)
+				}
+
+				summary.add(it)
+			}
+
+			summary.Vars = engine.Vars()
+
+			interrupted := scope.Err()
+			if interrupted != nil {
+				summary.Interrupted = interrupted.Error()
+
+				output.Printf("⚠️  Resumo parcial (%v): %d de %d iteração(ões) executada(s), %d com sucesso\n",
+					interrupted, len(summary.Iterations), quantity, summary.Succeeded)
+			}
+
+			if failedRows := summary.failedRows(); len(failedRows) > 0 {
+				output.Printf("📄 Linhas com falha em %s: %s\n", dataPath, strings.Join(failedRows, ", "))
+			}
+
+			if err := output.Render(summary, summary.table(), nil); err != nil {
+				return err
+			}
+
+			if interrupted != nil {
+				return interrupted
 			}
+
+			return summary.exitError(rpc)
 		},
 	}
 
 	cmd.Flags().IntVarP(&quantity, "quantity", "n", 1,
 		"Quantidade de vezes que o caso de teste será executado")
 	cmd.Flags().StringVar(&mockSpec, "mock", "",
-		"Array JSON ou lista separada por vírgulas de pares 'campo:tipo' para gerar dados dinâmicos")
+		"Array JSON ou lista separada por vírgulas de pares 'caminho:tipo' para gerar dados dinâmicos")
+	cmd.Flags().Int64Var(&seed, "seed", 0,
+		"Semente do --mock e dos placeholders int, float, bool e string para reproduzir os mesmos valores")
+	cmd.Flags().StringVar(&exchange, "exchange", "",
+		"Exchange de destino (sobrescreve o do caso de teste e o da configuração)")
+	cmd.Flags().StringVar(&vhost, "vhost", "",
+		"Virtual host de destino (sobrescreve o do caso de teste e o da configuração)")
+	cmd.Flags().StringVar(&transport, "transport", "",
+		"Transporte de publicação: http (API de gerenciamento) ou amqp")
+	cmd.Flags().StringArrayVarP(&properties, "property", "P", nil,
+		"Property AMQP no formato 'chave=valor' (ex: content_type=text/plain, priority=5); pode ser repetido")
+	cmd.Flags().BoolVar(&allowUnroutable, "allow-unroutable", false,
+		"Considera sucesso mensagens publicadas e não roteadas para nenhuma fila; no RPC, aguarda a resposta assim mesmo")
+	cmd.Flags().BoolVar(&rpc, "rpc", false,
+		"Modo request/reply: define reply_to e correlation_id e aguarda a resposta")
+	cmd.Flags().StringVar(&callOptions.ReplyQueue, "reply-queue", "",
+		"Fila de resposta existente para o modo --rpc (padrão: fila temporária)")
+	cmd.Flags().DurationVar(&callOptions.Timeout, "reply-timeout", 10*time.Second,
+		"Tempo máximo de espera pela resposta no modo --rpc")
+	retry.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&overallTimeout, "overall-timeout", 0,
+		"Tempo máximo da execução completa; publicações em andamento são canceladas (0 = sem limite)")
+	cmd.Flags().StringVar(&dataPath, "data", "",
+		"Arquivo CSV ou JSON Lines (.jsonl) com uma publicação por linha")
+	cmd.Flags().StringArrayVar(&vars, "var", nil,
+		"Variável no formato 'nome=valor', disponível como {{vars.nome}}; pode ser repetido")
 
 	return cmd
 }
 
-// randomString gera uma ‘string’ aleatória alfanumérica
-func randomString(n int, rng *rand.Rand) string {
-	letters := []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+// call executa uma chamada request/reply, repetindo falhas transitórias da
+// publicação, e exibe a resposta com a latência. Retorna erro apenas para
+// falhas de configuração, que se repetiriam em todas as iterações
+func (r *Run) call(
+	ctx context.Context,
+	i, quantity int,
+	tc rabbix.TestCase,
+	options request.CallOptions,
+	retry request.RetryPolicy,
+	it *Iteration,
+) (bool, error) {
+	reply, attempts, err := request.Call(ctx, r.request, tc, options, retry,
+		func(attempt int, reason string, wait time.Duration) {
+			output.Printf("🔁 [%d/%d] Tentativa %d/%d falhou (%s), nova tentativa em %v\n",
+				i, quantity, attempt, retry.MaxAttempts, reason, wait.Round(time.Millisecond))
+		})
+	it.Attempts = attempts
+	it.CorrelationID = reply.CorrelationID
+	it.Routed = reply.Routed
+
+	if err != nil {
+		if request.IsConfigError(err) {
+			return false, err
+		}
+
+		// Com --allow-unroutable a requisição sem rota só falha ao esgotar a espera
+		if errors.Is(err, request.ErrReplyTimeout) && !reply.Routed {
+			output.Printf("⚠️  [%d/%d] Requisição não roteada para nenhuma fila\n", i, quantity)
+		}
+
+		output.Printf("❌ [%d/%d] Chamada RPC falhou (correlation_id: %s): %v\n", i, quantity, reply.CorrelationID, err)
+		it.Error = err.Error()
+
+		return false, nil
+	}
+
+	it.ReplyQueue = reply.ReplyQueue
+	it.LatencyMs = output.Milliseconds(reply.Latency)
+
+	output.Printf("✅ [%d/%d] Resposta recebida em %v (correlation_id: %s, fila: %s)\n",
+		i, quantity, reply.Latency, reply.CorrelationID, reply.ReplyQueue)
+
+	body, err := reply.Message.Body()
+	if err != nil {
+		output.Printf("⚠️  [%d/%d] %v\n", i, quantity, err)
+		return true, nil
+	}
+
+	it.Response = string(body)
+
+	if headers := reply.Message.Headers(); len(headers) > 0 {
+		data, _ := json.Marshal(headers)
+		output.Printf("📋 [%d/%d] Headers: %s\n", i, quantity, string(data))
+	}
+
+	output.Printf("📥 [%d/%d] Payload da resposta:\n%s\n", i, quantity, string(body))
+
+	return true, nil
+}
+
+// capture guarda os valores do bloco capture nas variáveis e os exibe
+func (r *Run) capture(
+	i, quantity int,
+	engine *tmpl.Engine,
+	rules map[string]rabbix.Capture,
+	sources tmpl.Sources,
+	it *Iteration,
+) bool {
+	captured, err := engine.Capture(rules, sources)
+	if err != nil {
+		output.Printf("❌ [%d/%d] %v\n", i, quantity, err)
+		it.Error = err.Error()
 
-	b := make([]rune, n)
-	for i := range b {
-		b[i] = letters[rng.Intn(len(letters))]
+		return false
 	}
 
-	return string(b)
+	it.Captured = captured
+
+	for _, name := range rabbix.CaptureNames(rules) {
+		data, _ := json.Marshal(captured[name])
+		output.Printf("📌 [%d/%d] %s = %s\n", i, quantity, name, string(data))
+	}
+
+	return true
+}
+
+// expect aguarda a fila observada e exibe o resultado de cada asserção
+func (r *Run) expect(
+	ctx context.Context,
+	i, quantity int,
+	engine *tmpl.Engine,
+	tc rabbix.TestCase,
+	it *Iteration,
+) bool {
+	var result expect.Result
+
+	// Os valores esperados podem usar as variáveis capturadas da própria mensagem
+	rendered, err := expect.Render(*tc.Expect, engine)
+	if err == nil {
+		tc.Expect = &rendered
+		output.Printf("🔎 [%d/%d] Aguardando mensagens na fila '%s'...\n", i, quantity, rendered.Queue)
+
+		result, err = expect.Check(ctx, r.request, tc)
+	}
+
+	if err != nil {
+		output.Printf("❌ [%d/%d] %v\n", i, quantity, err)
+		it.Assertions = []expect.Assertion{{Name: "expect", Message: err.Error()}}
+
+		return false
+	}
+
+	it.Assertions = result.Assertions
+
+	for _, assertion := range result.Assertions {
+		output.Printf("   %s\n", assertion)
+	}
+
+	if !result.Passed() {
+		output.Printf("❌ [%d/%d] %d de %d asserção(ões) falharam\n",
+			i, quantity, result.Failed(), len(result.Assertions))
+
+		return false
+	}
+
+	output.Printf("✅ [%d/%d] Todas as %d asserção(ões) passaram\n", i, quantity, len(result.Assertions))
+
+	return true
 }
@@ -0,0 +1,136 @@
+package run
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/expect"
+	"github.com/maxwelbm/rabbix/pkg/output"
+)
+
+// Summary é o resultado do run na saída estruturada (--output json|yaml|table)
+type Summary struct {
+	Test            string `json:"test"`
+	RouteKey        string `json:"route_key"`
+	Quantity        int    `json:"quantity"`
+	Data            string `json:"data,omitempty"`
+	Succeeded       int    `json:"succeeded"`
+	Failed          int    `json:"failed"`
+	AssertionFailed int    `json:"assertion_failed"`
+	// Interrupted traz o motivo quando a execução foi interrompida antes do fim
+	Interrupted string `json:"interrupted,omitempty"`
+	// Vars são as variáveis ao final da execução, com as informadas em --var e as capturadas
+	Vars       map[string]any `json:"vars,omitempty"`
+	Iterations []Iteration    `json:"iterations"`
+}
+
+// Iteration é o resultado de uma publicação (ou chamada RPC) do run
+type Iteration struct {
+	Iteration     int                `json:"iteration"`
+	Row           int                `json:"row,omitempty"`
+	Success       bool               `json:"success"`
+	Status        int                `json:"status,omitempty"`
+	Routed        bool               `json:"routed"`
+	Attempts      int                `json:"attempts,omitempty"`
+	DurationMs    float64            `json:"duration_ms"`
+	Response      string             `json:"response,omitempty"`
+	Error         string             `json:"error,omitempty"`
+	CorrelationID string             `json:"correlation_id,omitempty"`
+	ReplyQueue    string             `json:"reply_queue,omitempty"`
+	LatencyMs     float64            `json:"latency_ms,omitempty"`
+	Captured      map[string]any     `json:"captured,omitempty"`
+	Assertions    []expect.Assertion `json:"assertions,omitempty"`
+}
+
+func (it Iteration) fail(message string, start time.Time) Iteration {
+	it.Error = message
+	it.DurationMs = output.Milliseconds(time.Since(start))
+
+	return it
+}
+
+// add contabiliza a iteração: falhas com asserções avaliadas são falhas do expect
+func (s *Summary) add(it Iteration) {
+	switch {
+	case it.Success:
+		s.Succeeded++
+	case len(it.Assertions) > 0:
+		s.AssertionFailed++
+	default:
+		s.Failed++
+	}
+
+	s.Iterations = append(s.Iterations, it)
+}
+
+// exitError define o código de saída do run: Publish quando todas as iterações
+// falharam, Partial quando só parte delas, e Assertion quando apenas o expect falhou
+func (s *Summary) exitError(rpc bool) error {
+	failure := "mensagem(ns) não publicada(s) com sucesso"
+	if rpc {
+		failure = "chamada(s) RPC sem resposta válida"
+	}
+
+	switch {
+	case s.Failed > 0 && s.Failed >= s.Quantity:
+		return exitcode.New(exitcode.Publish, "%d de %d %s", s.Failed, s.Quantity, failure)
+	case s.Failed > 0:
+		return exitcode.New(exitcode.Partial, "%d de %d %s", s.Failed, s.Quantity, failure)
+	case s.AssertionFailed > 0:
+		return exitcode.New(exitcode.Assertion,
+			"%d de %d execução(ões) com asserções do expect falhando", s.AssertionFailed, s.Quantity)
+	default:
+		return nil
+	}
+}
+
+// failedRows lista as linhas do --data cujas iterações falharam
+func (s *Summary) failedRows() []string {
+	var rows []string
+
+	for _, it := range s.Iterations {
+		if !it.Success && it.Row > 0 {
+			rows = append(rows, strconv.Itoa(it.Row))
+		}
+	}
+
+	return rows
+}
+
+func (s *Summary) table() *output.Table {
+	table := &output.Table{Header: []string{"#", "SUCESSO", "STATUS", "ROTEADA", "TENTATIVAS", "DURAÇÃO (ms)", "ERRO"}}
+	if s.Data != "" {
+		table.Header = append([]string{"LINHA"}, table.Header...)
+	}
+
+	for _, it := range s.Iterations {
+		message := it.Error
+		if message == "" {
+			for _, assertion := range it.Assertions {
+				if !assertion.Passed {
+					message = assertion.Detail()
+					break
+				}
+			}
+		}
+
+		row := []string{
+			strconv.Itoa(it.Iteration),
+			strconv.FormatBool(it.Success),
+			strconv.Itoa(it.Status),
+			strconv.FormatBool(it.Routed),
+			strconv.Itoa(it.Attempts),
+			strconv.FormatFloat(it.DurationMs, 'f', 3, 64),
+			message,
+		}
+
+		if s.Data != "" {
+			row = append([]string{strconv.Itoa(it.Row)}, row...)
+		}
+
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table
+}
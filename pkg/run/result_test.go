@@ -0,0 +1,108 @@
+package run
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/expect"
+)
+
+func TestSummaryAdd(t *testing.T) {
+	failedAssertion := []expect.Assertion{{Passed: true}, {Passed: false}}
+
+	tests := []struct {
+		name                string
+		iterations          []Iteration
+		wantSucceeded       int
+		wantFailed          int
+		wantAssertionFailed int
+		wantFailedRows      []string
+	}{
+		{name: "sem iterações"},
+		{
+			name:          "sucessos",
+			iterations:    []Iteration{{Success: true}, {Success: true, Assertions: []expect.Assertion{{Passed: true}}}},
+			wantSucceeded: 2,
+		},
+		{
+			name:          "falha de publicação",
+			iterations:    []Iteration{{Success: true}, {Error: "status 503"}},
+			wantSucceeded: 1,
+			wantFailed:    1,
+		},
+		{
+			name:                "falha do expect",
+			iterations:          []Iteration{{Assertions: failedAssertion}},
+			wantAssertionFailed: 1,
+		},
+		{
+			// Sem asserções avaliadas, a falha ao ler o expect conta como falha de publicação
+			name:       "expect não avaliado",
+			iterations: []Iteration{{Error: "asserções do expect não avaliadas"}},
+			wantFailed: 1,
+		},
+		{
+			name: "linhas do --data com falha",
+			iterations: []Iteration{
+				{Row: 2, Success: true},
+				{Row: 3, Error: "x"},
+				{Row: 4, Assertions: failedAssertion},
+			},
+			wantSucceeded:       1,
+			wantFailed:          1,
+			wantAssertionFailed: 1,
+			wantFailedRows:      []string{"3", "4"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var summary Summary
+			for _, it := range tt.iterations {
+				summary.add(it)
+			}
+
+			if summary.Succeeded != tt.wantSucceeded || summary.Failed != tt.wantFailed ||
+				summary.AssertionFailed != tt.wantAssertionFailed {
+				t.Errorf("add = %d sucesso(s), %d falha(s), %d do expect; esperado %d, %d, %d",
+					summary.Succeeded, summary.Failed, summary.AssertionFailed,
+					tt.wantSucceeded, tt.wantFailed, tt.wantAssertionFailed)
+			}
+
+			if len(summary.Iterations) != len(tt.iterations) {
+				t.Errorf("add guardou %d iteração(ões), esperado %d", len(summary.Iterations), len(tt.iterations))
+			}
+
+			if got := summary.failedRows(); !reflect.DeepEqual(got, tt.wantFailedRows) {
+				t.Errorf("failedRows = %q, esperado %q", got, tt.wantFailedRows)
+			}
+		})
+	}
+}
+
+func TestSummaryExitError(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary Summary
+		rpc     bool
+		want    int
+	}{
+		{name: "sucesso", summary: Summary{Quantity: 3, Succeeded: 3}, want: exitcode.Success},
+		{name: "todas falharam", summary: Summary{Quantity: 3, Failed: 3}, want: exitcode.Publish},
+		{name: "parte falhou", summary: Summary{Quantity: 3, Succeeded: 2, Failed: 1}, want: exitcode.Partial},
+		{name: "rpc parcial", summary: Summary{Quantity: 2, Succeeded: 1, Failed: 1}, rpc: true, want: exitcode.Partial},
+		{
+			name:    "falhas de publicação e do expect",
+			summary: Summary{Quantity: 2, Failed: 1, AssertionFailed: 1},
+			want:    exitcode.Partial,
+		},
+		{name: "apenas expect", summary: Summary{Quantity: 2, Succeeded: 1, AssertionFailed: 1}, want: exitcode.Assertion},
+	}
+
+	for _, tt := range tests {
+		if got := exitcode.Code(tt.summary.exitError(tt.rpc)); got != tt.want {
+			t.Errorf("%s: exitError = %d, esperado %d", tt.name, got, tt.want)
+		}
+	}
+}
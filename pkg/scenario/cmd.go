@@ -0,0 +1,349 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/interrupt"
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	"github.com/maxwelbm/rabbix/pkg/request"
+	"github.com/maxwelbm/rabbix/pkg/sett"
+	"github.com/maxwelbm/rabbix/pkg/tmpl"
+	"github.com/spf13/cobra"
+)
+
+// extensions são os formatos aceitos para os arquivos de cenário, em ordem de busca
+var extensions = []string{".yaml", ".yml", ".json"}
+
+type Scenario struct {
+	settings sett.SettItf
+	request  request.RequestItf
+}
+
+// options são as flags do comando aplicadas a todos os passos
+type options struct {
+	exchange        string
+	vhost           string
+	transport       string
+	allowUnroutable bool
+	retry           request.RetryPolicy
+}
+
+func New(
+	settings sett.SettItf,
+	request request.RequestItf,
+) *Scenario {
+	return &Scenario{
+		settings: settings,
+		request:  request,
+	}
+}
+
+func (s *Scenario) CmdScenario() *cobra.Command {
+	var (
+		opts           options
+		vars           []string
+		overallTimeout time.Duration
+	)
+
+	var cmd = &cobra.Command{
+		Use:   "scenario [arquivo|nome]",
+		Short: "Executa um cenário com passos ordenados",
+		Long: `Executa um cenário: uma sequência ordenada de passos descrita em YAML ou JSON.
+Cada passo pode aguardar um intervalo, publicar um caso de teste salvo, capturar
+valores da mensagem e verificar uma fila com um bloco expect. As variáveis
+capturadas ficam disponíveis nos passos seguintes como {{vars.nome}}.
+O capture do passo se soma ao do teste e aceita a forma curta (caminho no payload
+publicado) ou {from: payload|headers|reply, path: ...}; from: reply exige 'rpc: true',
+que publica com reply_to e aguarda a resposta por até reply_timeout (padrão: 10s).
+O cenário é lido do caminho informado ou de <output_dir>/scenarios/<nome>.yaml.
+
+Exemplo de cenário:
+  name: pedido-completo
+  vars:
+    tenant: acme
+  steps:
+    - test: pedido-criado
+      set:
+        tenant: "{{vars.tenant}}"
+      capture:
+        orderId: $.order.id
+    - wait: 500ms
+    - name: pagamento
+      test: pagamento-aprovado
+      set:
+        order.id: "{{vars.orderId}}"
+      depends_on: [pedido-criado]
+    - test: consulta-status
+      rpc: true
+      capture:
+        status: {from: reply, path: $.status}
+    - name: pedido-enviado
+      expect:
+        queue: pedidos.enviados
+        payload:
+          $.order.id: "{{vars.orderId}}"
+
+Por padrão o cenário para no primeiro passo com falha; com 'continue_on_error: true'
+apenas os passos que dependem (depends_on) de um passo com falha são pulados.
+Exemplos:
+  rabbix scenario pedido-completo.yaml
+  rabbix scenario pedido-completo --var tenant=globex -o json`,
+		Args:          cobra.ExactArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			// Sugere os cenários salvos, mantendo a completação de arquivos
+			return s.savedScenarios(), cobra.ShellCompDirectiveDefault
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputDir := s.outputDir()
+
+			path, err := s.resolve(outputDir, args[0])
+			if err != nil {
+				output.Println("💡 Informe o caminho do arquivo ou salve o cenário em " +
+					filepath.Join(outputDir, "scenarios"))
+				return exitcode.Wrap(exitcode.NotFound, err)
+			}
+
+			sc, err := Load(path)
+			if err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
+			if err := opts.retry.Validate(); err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
+			if overallTimeout < 0 {
+				return exitcode.New(exitcode.Config, "--overall-timeout não pode ser negativo")
+			}
+
+			engine := tmpl.New()
+
+			for name, value := range sc.Vars {
+				engine.SetVar(name, value)
+			}
+
+			// As variáveis da linha de comando sobrescrevem as do arquivo
+			if err := engine.SetVarPairs(vars); err != nil {
+				return exitcode.Wrap(exitcode.Config, err)
+			}
+
+			total := len(sc.Steps)
+
+			output.Printf("🎬 Cenário: %s (%d passo(s))\n", sc.Name, total)
+
+			if sc.Description != "" {
+				output.Printf("📝 %s\n", sc.Description)
+			}
+
+			output.Println("─────────────────────────────────────")
+
+			// Mantém a conexão do transporte aberta durante todos os passos
+			defer func() {
+				if err := s.request.Close(); err != nil {
+					output.Printf("❌ Erro ao encerrar conexão: %v\n", err)
+				}
+			}()
+
+			// Ctrl+C interrompe os próximos passos; o tempo total cancela também o atual
+			scope := interrupt.New(cmd.Context(), overallTimeout)
+			defer scope.Close()
+
+			startTime := time.Now()
+			summary := Summary{Scenario: sc.Name, File: path}
+
+			s.purgeObserved(scope, sc, opts)
+
+			// notPassed guarda os passos que falharam ou foram pulados, para as dependências
+			notPassed := map[string]bool{}
+			stopped := ""
+
+			for i, step := range sc.Steps {
+				result := StepResult{Step: i + 1, Name: step.StepName(i), Test: step.Test}
+
+				switch reason := skipReason(step, notPassed, stopped); {
+				case reason != "":
+					result = result.skip(reason)
+				case scope.Stopped():
+					result = result.skip("execução interrompida")
+				default:
+					result = s.step(scope, i, total, step, outputDir, engine, opts, result)
+				}
+
+				if result.Status == StatusSkipped {
+					output.Printf("⏭️  [%d/%d] %s: PULADO (%s)\n", i+1, total, result.Name, result.Error)
+				}
+
+				if result.Status != StatusPassed {
+					notPassed[result.Name] = true
+
+					if result.Status == StatusFailed && !sc.ContinueOnError && stopped == "" {
+						stopped = result.Name
+					}
+				}
+
+				summary.add(result)
+			}
+
+			summary.DurationMs = output.Milliseconds(time.Since(startTime))
+			summary.Vars = engine.Vars()
+
+			output.Println("─────────────────────────────────────")
+			output.Printf("📊 Resumo do cenário: ✅ %d | ❌ %d | ⏭️  %d | ⏱️  %v\n",
+				summary.Passed, summary.Failed+summary.AssertionFailed, summary.Skipped,
+				time.Since(startTime).Round(time.Millisecond))
+
+			interrupted := scope.Err()
+			if interrupted != nil {
+				summary.Interrupted = interrupted.Error()
+				output.Printf("⚠️  Resumo parcial (%v)\n", interrupted)
+			}
+
+			if err := output.Render(summary, summary.table(), nil); err != nil {
+				return err
+			}
+
+			switch {
+			case interrupted != nil:
+				return interrupted
+			case summary.Failed > 0:
+				return exitcode.New(exitcode.Publish, "%d de %d passo(s) do cenário falharam", summary.Failed, total)
+			case summary.AssertionFailed > 0:
+				return exitcode.New(exitcode.Assertion,
+					"%d de %d passo(s) do cenário com asserções do expect falhando", summary.AssertionFailed, total)
+			default:
+				return nil
+			}
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&vars, "var", nil,
+		"Variável no formato 'nome=valor', disponível como {{vars.nome}}; pode ser repetido")
+	cmd.Flags().StringVar(&opts.exchange, "exchange", "",
+		"Exchange de destino para todos os passos (sobrescreve o dos casos de teste e o da configuração)")
+	cmd.Flags().StringVar(&opts.vhost, "vhost", "",
+		"Virtual host de destino para todos os passos (sobrescreve o dos casos de teste e o da configuração)")
+	cmd.Flags().StringVar(&opts.transport, "transport", "",
+		"Transporte de publicação: http (API de gerenciamento) ou amqp")
+	cmd.Flags().BoolVar(&opts.allowUnroutable, "allow-unroutable", false,
+		"Considera sucesso mensagens publicadas e não roteadas para nenhuma fila; no RPC, aguarda a resposta assim mesmo")
+	opts.retry.AddFlags(cmd.Flags())
+	cmd.Flags().DurationVar(&overallTimeout, "overall-timeout", 0,
+		"Tempo máximo do cenário completo; o passo em andamento é cancelado (0 = sem limite)")
+
+	return cmd
+}
+
+// skipReason indica por que o passo não deve executar, ou vazio quando ele pode executar
+func skipReason(step rabbix.Step, notPassed map[string]bool, stopped string) string {
+	if stopped != "" {
+		return fmt.Sprintf("o passo '%s' falhou", stopped)
+	}
+
+	for _, dependency := range step.DependsOn {
+		if notPassed[dependency] {
+			return fmt.Sprintf("depende de '%s', que não teve sucesso", dependency)
+		}
+	}
+
+	return ""
+}
+
+// Load lê e valida um arquivo de cenário em YAML ou JSON
+func Load(path string) (rabbix.Scenario, error) {
+	var sc rabbix.Scenario
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return sc, fmt.Errorf("erro ao ler cenário: %w", err)
+	}
+
+	if err := decode(data, &sc); err != nil {
+		return sc, fmt.Errorf("erro ao carregar cenário '%s': %w", path, err)
+	}
+
+	if err := sc.Validate(); err != nil {
+		return sc, fmt.Errorf("cenário '%s' inválido: %w", path, err)
+	}
+
+	return sc, nil
+}
+
+func (s *Scenario) outputDir() string {
+	outputDir := s.settings.LoadSettings()["output_dir"]
+	if outputDir == "" {
+		home, _ := os.UserHomeDir()
+		outputDir = filepath.Join(home, ".rabbix", "tests")
+	}
+
+	return outputDir
+}
+
+// resolve aceita o caminho de um arquivo ou o nome de um cenário salvo em <output_dir>/scenarios
+func (s *Scenario) resolve(outputDir, arg string) (string, error) {
+	if info, err := os.Stat(arg); err == nil && !info.IsDir() {
+		return arg, nil
+	}
+
+	for _, ext := range extensions {
+		path := filepath.Join(outputDir, "scenarios", arg+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("cenário '%s' não encontrado", arg)
+}
+
+// savedScenarios lista os nomes dos cenários salvos para o autocomplete
+func (s *Scenario) savedScenarios() []string {
+	files, err := os.ReadDir(filepath.Join(s.outputDir(), "scenarios"))
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		for _, accepted := range extensions {
+			if ext == accepted {
+				names = append(names, strings.TrimSuffix(file.Name(), ext))
+			}
+		}
+	}
+
+	return names
+}
+
+// readTest carrega um caso de teste salvo no output_dir
+func readTest(outputDir, name string) (rabbix.TestCase, error) {
+	var tc rabbix.TestCase
+
+	data, err := os.ReadFile(filepath.Join(outputDir, name+".json"))
+	if err != nil {
+		return tc, fmt.Errorf("teste '%s' não encontrado em %s", name, outputDir)
+	}
+
+	if err := json.Unmarshal(data, &tc); err != nil {
+		return tc, fmt.Errorf("erro ao carregar JSON do teste '%s': %w", name, err)
+	}
+
+	if tc.Name == "" {
+		tc.Name = name
+	}
+
+	return tc, nil
+}
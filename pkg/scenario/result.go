@@ -0,0 +1,120 @@
+package scenario
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/expect"
+	"github.com/maxwelbm/rabbix/pkg/output"
+)
+
+const (
+	// StatusPassed indica um passo executado com sucesso
+	StatusPassed = "passed"
+	// StatusFailed indica um passo com falha na publicação, na captura ou no expect
+	StatusFailed = "failed"
+	// StatusSkipped indica um passo não executado por dependência ou interrupção
+	StatusSkipped = "skipped"
+)
+
+// Summary é o resultado do cenário na saída estruturada (--output json|yaml|table)
+type Summary struct {
+	Scenario        string         `json:"scenario"`
+	File            string         `json:"file"`
+	Passed          int            `json:"passed"`
+	Failed          int            `json:"failed"`
+	AssertionFailed int            `json:"assertion_failed"`
+	Skipped         int            `json:"skipped"`
+	DurationMs      float64        `json:"duration_ms"`
+	Interrupted     string         `json:"interrupted,omitempty"`
+	Vars            map[string]any `json:"vars,omitempty"`
+	Steps           []StepResult   `json:"steps"`
+}
+
+// StepResult é o resultado de um passo do cenário
+type StepResult struct {
+	Step       int                `json:"step"`
+	Name       string             `json:"name"`
+	Test       string             `json:"test,omitempty"`
+	Status     string             `json:"status"`
+	DurationMs float64            `json:"duration_ms"`
+	HTTPStatus int                `json:"http_status,omitempty"`
+	Routed     bool               `json:"routed,omitempty"`
+	Attempts   int                `json:"attempts,omitempty"`
+	Response   string             `json:"response,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	Captured   map[string]any     `json:"captured,omitempty"`
+	Assertions []expect.Assertion `json:"assertions,omitempty"`
+}
+
+// add contabiliza o passo: falhas com asserções avaliadas são falhas do expect
+func (s *Summary) add(step StepResult) {
+	switch {
+	case step.Status == StatusPassed:
+		s.Passed++
+	case step.Status == StatusSkipped:
+		s.Skipped++
+	case step.assertionFailed():
+		s.AssertionFailed++
+	default:
+		s.Failed++
+	}
+
+	s.Steps = append(s.Steps, step)
+}
+
+func (r StepResult) assertionFailed() bool {
+	for _, assertion := range r.Assertions {
+		if !assertion.Passed {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r StepResult) fail(message string, start time.Time) StepResult {
+	r.Status = StatusFailed
+	r.Error = message
+	r.DurationMs = output.Milliseconds(time.Since(start))
+
+	return r
+}
+
+func (r StepResult) skip(reason string) StepResult {
+	r.Status = StatusSkipped
+	r.Error = reason
+
+	return r
+}
+
+func (s *Summary) table() *output.Table {
+	table := &output.Table{
+		Header: []string{"#", "PASSO", "TESTE", "RESULTADO", "STATUS", "TENTATIVAS", "DURAÇÃO (ms)", "ERRO"},
+	}
+
+	for _, step := range s.Steps {
+		message := step.Error
+		if message == "" {
+			for _, assertion := range step.Assertions {
+				if !assertion.Passed {
+					message = assertion.Detail()
+					break
+				}
+			}
+		}
+
+		table.Rows = append(table.Rows, []string{
+			strconv.Itoa(step.Step),
+			step.Name,
+			step.Test,
+			step.Status,
+			strconv.Itoa(step.HTTPStatus),
+			strconv.Itoa(step.Attempts),
+			strconv.FormatFloat(step.DurationMs, 'f', 3, 64),
+			message,
+		})
+	}
+
+	return table
+}
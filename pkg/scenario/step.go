@@ -0,0 +1,386 @@
+package scenario
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/expect"
+	"github.com/maxwelbm/rabbix/pkg/interrupt"
+	"github.com/maxwelbm/rabbix/pkg/jpath"
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+	"github.com/maxwelbm/rabbix/pkg/request"
+	"github.com/maxwelbm/rabbix/pkg/tmpl"
+	"gopkg.in/yaml.v3"
+)
+
+// step executa um passo: espera, publicação, captura e expect, nessa ordem
+func (s *Scenario) step(
+	scope *interrupt.Scope,
+	index, total int,
+	step rabbix.Step,
+	outputDir string,
+	engine *tmpl.Engine,
+	opts options,
+	result StepResult,
+) StepResult {
+	prefix := fmt.Sprintf("[%d/%d] %s", index+1, total, result.Name)
+
+	// A validação do cenário já garantiu que a duração é válida
+	if wait, _ := step.WaitDuration(); wait > 0 {
+		output.Printf("⏳ %s: aguardando %v\n", prefix, wait)
+
+		if !scope.Sleep(wait) {
+			return result.skip("execução interrompida")
+		}
+	}
+
+	start := time.Now()
+
+	var tc rabbix.TestCase
+
+	if step.Test != "" {
+		output.Printf("🔄 %s: publicando %s\n", prefix, step.Test)
+
+		msg, err := s.prepare(step, outputDir, engine, opts)
+		if err != nil {
+			output.Printf("❌ %s: %v\n", prefix, err)
+			return result.fail(err.Error(), start)
+		}
+
+		sources := tmpl.SourcesOf(msg)
+
+		if step.RPC {
+			var body []byte
+
+			if body, err = s.call(scope, prefix, msg, step, opts, &result); err == nil {
+				sources = sources.WithReply(body)
+			}
+		} else {
+			err = s.publish(scope, prefix, msg, opts, &result)
+		}
+
+		if err != nil {
+			output.Printf("❌ %s: %v\n", prefix, err)
+			return result.fail(err.Error(), start)
+		}
+
+		if result.Captured, err = engine.Capture(msg.Capture, sources); err != nil {
+			output.Printf("❌ %s: %v\n", prefix, err)
+			return result.fail(err.Error(), start)
+		}
+
+		printCaptured(prefix, result.Captured)
+
+		tc = msg
+	} else {
+		tc = rabbix.TestCase{Vhost: opts.vhost, Transport: opts.transport, Expect: step.Expect}
+	}
+
+	if tc.Expect != nil {
+		if err := s.expect(scope, prefix, tc, engine, &result); err != nil {
+			output.Printf("❌ %s: %v\n", prefix, err)
+			return result.fail(err.Error(), start)
+		}
+	}
+
+	result.DurationMs = output.Milliseconds(time.Since(start))
+
+	if result.Status == "" {
+		result.Status = StatusPassed
+	}
+
+	return result
+}
+
+// purgeObserved esvazia, antes do primeiro passo, as filas dos passos só com expect
+// e purge, que observam o efeito dos passos anteriores; os passos que publicam
+// esvaziam a própria fila antes da publicação
+func (s *Scenario) purgeObserved(scope *interrupt.Scope, sc rabbix.Scenario, opts options) {
+	purged := map[string]bool{}
+
+	for _, step := range sc.Steps {
+		if step.Test != "" || step.Expect == nil || !step.Expect.Purge {
+			continue
+		}
+
+		key := step.Expect.Vhost + "/" + step.Expect.Queue
+		if purged[key] {
+			continue
+		}
+
+		purged[key] = true
+
+		tc := rabbix.TestCase{Vhost: opts.vhost, Transport: opts.transport, Expect: step.Expect}
+		if err := expect.Prepare(scope.Work, s.request, tc); err != nil {
+			output.Printf("⚠️  %v; mensagens antigas da fila podem ser avaliadas\n", err)
+		}
+	}
+}
+
+// prepare carrega o caso de teste, aplica as sobrescritas do passo e das flags
+// e avalia os placeholders, inclusive as variáveis capturadas
+func (s *Scenario) prepare(
+	step rabbix.Step,
+	outputDir string,
+	engine *tmpl.Engine,
+	opts options,
+) (rabbix.TestCase, error) {
+	tc, err := readTest(outputDir, step.Test)
+	if err != nil {
+		return tc, err
+	}
+
+	if opts.exchange != "" {
+		tc.Exchange = opts.exchange
+	}
+
+	if opts.vhost != "" {
+		tc.Vhost = opts.vhost
+	}
+
+	if opts.transport != "" {
+		tc.Transport = opts.transport
+	}
+
+	if step.RouteKey != "" {
+		tc.RouteKey = step.RouteKey
+	}
+
+	if len(step.Set) > 0 && tc.Payload != nil {
+		return tc, fmt.Errorf("'set' exige um teste com json_pool, o teste '%s' usa 'payload'", step.Test)
+	}
+
+	if tc.JSONPool == nil {
+		tc.JSONPool = map[string]any{}
+	}
+
+	for path, value := range step.Set {
+		if err := jpath.Set(tc.JSONPool, path, value); err != nil {
+			return tc, fmt.Errorf("set: %w", err)
+		}
+	}
+
+	if len(step.Headers) > 0 && tc.Headers == nil {
+		tc.Headers = map[string]any{}
+	}
+
+	for key, value := range step.Headers {
+		tc.Headers[key] = value
+	}
+
+	if step.Expect != nil {
+		tc.Expect = step.Expect
+	}
+
+	// As regras do passo se somam às do teste, com precedência
+	if len(step.Capture) > 0 {
+		rules := make(map[string]rabbix.Capture, len(tc.Capture)+len(step.Capture))
+		for name, rule := range tc.Capture {
+			rules[name] = rule
+		}
+
+		for name, rule := range step.Capture {
+			rules[name] = rule
+		}
+
+		tc.Capture = rules
+	}
+
+	if err := rabbix.ValidateCaptures(tc.Capture); err != nil {
+		return tc, fmt.Errorf("capture inválido no teste '%s': %w", step.Test, err)
+	}
+
+	if rabbix.UsesReply(tc.Capture) && !step.RPC {
+		return tc, fmt.Errorf("o teste '%s' captura da resposta (from: reply), use 'rpc: true' no passo", step.Test)
+	}
+
+	msg, err := engine.RenderTestCase(tc)
+	if err != nil {
+		return msg, fmt.Errorf("erro ao avaliar placeholders: %w", err)
+	}
+
+	return msg, nil
+}
+
+// publish publica a mensagem e registra status, roteamento e resposta no resultado
+func (s *Scenario) publish(
+	scope *interrupt.Scope,
+	prefix string,
+	msg rabbix.TestCase,
+	opts options,
+	result *StepResult,
+) error {
+	// Com expect.purge, mensagens antigas da fila não contam para esta publicação
+	if err := expect.Prepare(scope.Work, s.request, msg); err != nil {
+		return err
+	}
+
+	resp, attempts, err := request.Publish(scope.Work, s.request, msg, opts.retry,
+		func(attempt int, reason string, wait time.Duration) {
+			output.Printf("🔁 %s: tentativa %d/%d falhou (%s), nova tentativa em %v\n",
+				prefix, attempt, opts.retry.MaxAttempts, reason, wait.Round(time.Millisecond))
+		})
+	result.Attempts = attempts
+
+	if err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("erro ao ler resposta: %w", err)
+	}
+
+	result.HTTPStatus = resp.StatusCode
+	result.Response = string(body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("status HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	published, err := request.ParseResult(body)
+	if err != nil {
+		return err
+	}
+
+	result.Routed = published.Routed
+
+	switch {
+	case published.Routed:
+		output.Printf("✅ %s: OK (Status: %d)\n", prefix, resp.StatusCode)
+	case opts.allowUnroutable:
+		output.Printf("⚠️  %s: publicada, mas não roteada para nenhuma fila\n", prefix)
+	default:
+		return fmt.Errorf("mensagem não roteada para nenhuma fila (verifique route key e exchange)")
+	}
+
+	return nil
+}
+
+// call executa o passo no modo request/reply e retorna o payload da resposta
+func (s *Scenario) call(
+	scope *interrupt.Scope,
+	prefix string,
+	msg rabbix.TestCase,
+	step rabbix.Step,
+	opts options,
+	result *StepResult,
+) ([]byte, error) {
+	// A validação do cenário já garantiu que a duração é válida
+	timeout, _ := step.ReplyTimeoutDuration()
+	callOptions := request.CallOptions{Timeout: timeout, AllowUnroutable: opts.allowUnroutable}
+
+	reply, attempts, err := request.Call(scope.Work, s.request, msg, callOptions, opts.retry,
+		func(attempt int, reason string, wait time.Duration) {
+			output.Printf("🔁 %s: tentativa %d/%d falhou (%s), nova tentativa em %v\n",
+				prefix, attempt, opts.retry.MaxAttempts, reason, wait.Round(time.Millisecond))
+		})
+	result.Attempts = attempts
+	result.Routed = reply.Routed
+
+	if err != nil {
+		// Com --allow-unroutable a requisição sem rota só falha ao esgotar a espera
+		if errors.Is(err, request.ErrReplyTimeout) && !reply.Routed {
+			output.Printf("⚠️  %s: requisição não roteada para nenhuma fila\n", prefix)
+		}
+
+		return nil, fmt.Errorf("chamada RPC falhou (correlation_id: %s): %w", reply.CorrelationID, err)
+	}
+
+	body, err := reply.Message.Body()
+	if err != nil {
+		return nil, err
+	}
+
+	result.Response = string(body)
+
+	output.Printf("✅ %s: resposta recebida em %v (correlation_id: %s)\n", prefix, reply.Latency, reply.CorrelationID)
+
+	return body, nil
+}
+
+// printCaptured exibe as variáveis capturadas em ordem alfabética
+func printCaptured(prefix string, captured map[string]any) {
+	names := make([]string, 0, len(captured))
+	for name := range captured {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		data, _ := json.Marshal(captured[name])
+		output.Printf("📌 %s: %s = %s\n", prefix, name, string(data))
+	}
+}
+
+// expect avalia o bloco expect com os valores esperados já com as variáveis
+// avaliadas. Falhas nas asserções não são erros: ficam no resultado do passo
+func (s *Scenario) expect(
+	scope *interrupt.Scope,
+	prefix string,
+	tc rabbix.TestCase,
+	engine *tmpl.Engine,
+	result *StepResult,
+) error {
+	rendered, err := expect.Render(*tc.Expect, engine)
+	if err != nil {
+		return err
+	}
+
+	tc.Expect = &rendered
+
+	output.Printf("🔎 %s: aguardando mensagens na fila '%s'...\n", prefix, rendered.Queue)
+
+	check, err := expect.Check(scope.Work, s.request, tc)
+	if err != nil {
+		return err
+	}
+
+	result.Assertions = check.Assertions
+
+	for _, assertion := range check.Assertions {
+		output.Printf("   %s\n", assertion)
+	}
+
+	if !check.Passed() {
+		result.Status = StatusFailed
+		output.Printf("❌ %s: %d de %d asserção(ões) falharam\n", prefix, check.Failed(), len(check.Assertions))
+
+		return nil
+	}
+
+	output.Printf("✅ %s: %d asserção(ões) OK\n", prefix, len(check.Assertions))
+
+	return nil
+}
+
+// decode lê o cenário em YAML ou JSON. O YAML é convertido para JSON para
+// reaproveitar as tags json dos tipos do rabbix, e campos desconhecidos são
+// rejeitados para apontar erros de digitação
+func decode(data []byte, sc *rabbix.Scenario) error {
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(sc)
+}
@@ -0,0 +1,348 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/maxwelbm/rabbix/pkg/cache"
+	"github.com/maxwelbm/rabbix/pkg/exitcode"
+	"github.com/maxwelbm/rabbix/pkg/output"
+	"github.com/maxwelbm/rabbix/pkg/sett"
+	"github.com/spf13/cobra"
+)
+
+type Test struct {
+	settings sett.SettItf
+	Cache    cache.CacheItf
+}
+
+func New(
+	settings sett.SettItf,
+	cache cache.CacheItf,
+) *Test {
+	return &Test{
+		settings: settings,
+		Cache:    cache,
+	}
+}
+
+func (t *Test) CmdTest() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Remove, renomeia e copia casos de teste",
+	}
+
+	cmd.AddCommand(t.cmdRm())
+	cmd.AddCommand(t.cmdMv())
+	cmd.AddCommand(t.cmdCp())
+
+	return cmd
+}
+
+func (t *Test) cmdRm() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "rm [test-name...]",
+		Short: "Remove casos de teste",
+		Long: `Remove um ou mais casos de teste do output_dir da configuração ativa e atualiza o cache.
+A remoção pede confirmação; use --force para remover sem perguntar.
+Exemplos:
+  rabbix test rm meu-teste
+  rabbix test rm teste-a teste-b --force`,
+		Args:          cobra.MinimumNArgs(1),
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			// Sincroniza cache antes de fornecer sugestões
+			t.Cache.SyncCacheWithFileSystem()
+
+			var suggestions []string
+
+			for _, name := range t.Cache.GetCachedTests() {
+				if !slices.Contains(args, name) {
+					suggestions = append(suggestions, name)
+				}
+			}
+
+			return suggestions, cobra.ShellCompDirectiveNoFileComp
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputDir := t.outputDir()
+
+			// Confere todos os testes antes de remover qualquer um
+			for _, name := range args {
+				if err := validName(name); err != nil {
+					return err
+				}
+
+				if _, err := os.Stat(filepath.Join(outputDir, name+".json")); err != nil {
+					output.Println("💡 Use 'rabbix list' para ver os testes disponíveis")
+					return exitcode.New(exitcode.NotFound, "teste '%s' não encontrado em %s", name, outputDir)
+				}
+			}
+
+			if !force {
+				output.Println("🗑️  Testes a remover:")
+				for _, name := range args {
+					output.Printf("   • %s\n", name)
+				}
+
+				confirmed, err := confirm(bufio.NewReader(cmd.InOrStdin()), len(args))
+				if err != nil {
+					return err
+				}
+
+				if !confirmed {
+					output.Println("🚫 Remoção cancelada")
+					return nil
+				}
+			}
+
+			var removeErr error
+
+			for _, name := range args {
+				if err := os.Remove(filepath.Join(outputDir, name+".json")); err != nil {
+					removeErr = fmt.Errorf("erro ao remover teste '%s': %w", name, err)
+					break
+				}
+
+				output.Printf("✅ Teste '%s' removido\n", name)
+			}
+
+			// Atualiza o cache mesmo se parte dos testes já foi removida
+			t.Cache.SyncCacheWithFileSystem()
+
+			return removeErr
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Remove sem pedir confirmação")
+
+	return cmd
+}
+
+func (t *Test) cmdMv() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "mv [test-name] [new-name]",
+		Short: "Renomeia um caso de teste",
+		Long: `Renomeia um caso de teste no output_dir da configuração ativa.
+O campo "name" do JSON acompanha o novo nome quando era igual ao nome do arquivo,
+e a entrada do cache é renomeada mantendo a data de criação.
+Um teste existente com o novo nome só é sobrescrito com --force.
+Exemplos:
+  rabbix test mv meu-teste pedido-criado
+  rabbix test mv rascunho pedido-criado --force`,
+		Args:              cobra.ExactArgs(2),
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		ValidArgsFunction: t.completeSource,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, to := args[0], args[1]
+
+			if err := t.transfer(from, to, force); err != nil {
+				return err
+			}
+
+			if err := os.Remove(filepath.Join(t.outputDir(), from+".json")); err != nil {
+				return fmt.Errorf("erro ao remover teste '%s': %w", from, err)
+			}
+
+			t.Cache.RenameCachedTest(from, to)
+			t.Cache.SyncCacheWithFileSystem()
+
+			output.Printf("✅ Teste '%s' renomeado para '%s'\n", from, to)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Sobrescreve um teste existente com o novo nome")
+
+	return cmd
+}
+
+func (t *Test) cmdCp() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "cp [test-name] [new-name]",
+		Short: "Copia um caso de teste",
+		Long: `Duplica um caso de teste no output_dir da configuração ativa e adiciona a cópia ao cache.
+O campo "name" da cópia recebe o novo nome quando era igual ao nome do arquivo original.
+Um teste existente com o novo nome só é sobrescrito com --force.
+Exemplos:
+  rabbix test cp pedido-criado pedido-cancelado
+  rabbix test cp pedido-criado pedido-cancelado --force`,
+		Args:              cobra.ExactArgs(2),
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+		ValidArgsFunction: t.completeSource,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, to := args[0], args[1]
+
+			if err := t.transfer(from, to, force); err != nil {
+				return err
+			}
+
+			t.Cache.SyncCacheWithFileSystem()
+
+			output.Printf("✅ Teste '%s' copiado para '%s'\n", from, to)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Sobrescreve um teste existente com o novo nome")
+
+	return cmd
+}
+
+// completeSource sugere apenas o teste de origem; o novo nome é livre
+func (t *Test) completeSource(
+	cmd *cobra.Command, args []string, toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Sincroniza cache antes de fornecer sugestões
+	t.Cache.SyncCacheWithFileSystem()
+
+	return t.Cache.GetCachedTests(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// transfer grava o teste 'from' com o nome 'to', sem remover a origem
+func (t *Test) transfer(from, to string, force bool) error {
+	for _, name := range []string{from, to} {
+		if err := validName(name); err != nil {
+			return err
+		}
+	}
+
+	if from == to {
+		return exitcode.New(exitcode.Config, "o novo nome é igual ao atual ('%s')", from)
+	}
+
+	outputDir := t.outputDir()
+	source := filepath.Join(outputDir, from+".json")
+	target := filepath.Join(outputDir, to+".json")
+
+	data, err := os.ReadFile(source)
+	if err != nil {
+		output.Println("💡 Use 'rabbix list' para ver os testes disponíveis")
+		return exitcode.New(exitcode.NotFound, "teste '%s' não encontrado em %s", from, source)
+	}
+
+	if _, err := os.Stat(target); err == nil && !force {
+		return exitcode.New(exitcode.Config, "o teste '%s' já existe em %s (use --force para sobrescrever)", to, target)
+	}
+
+	// O campo "name" só acompanha o arquivo quando era igual ao nome antigo
+	data = renameField(data, from, to)
+
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		return fmt.Errorf("erro ao salvar teste: %w", err)
+	}
+
+	return nil
+}
+
+func (t *Test) outputDir() string {
+	settings := t.settings.LoadSettings()
+
+	outputDir := settings["output_dir"]
+	if outputDir == "" {
+		home, _ := os.UserHomeDir()
+		outputDir = filepath.Join(home, ".rabbix", "tests")
+	}
+
+	return outputDir
+}
+
+func validName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return exitcode.New(exitcode.Config, "o nome do teste não pode ser vazio")
+	}
+
+	if strings.ContainsAny(name, `/\`) {
+		return exitcode.New(exitcode.Config, "nome inválido '%s': não pode conter separadores de diretório", name)
+	}
+
+	return nil
+}
+
+// renameField troca o valor do campo "name" no nível raiz quando ele é igual a
+// 'from'. Só os bytes do valor mudam; formatação, ordem das chaves e os demais
+// valores, como números acima de 2^53, ficam como estavam no arquivo
+func renameField(data []byte, from, to string) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return data
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return data
+		}
+
+		// O valor começa depois do ':' que segue a chave
+		start := decoder.InputOffset()
+		start += int64(bytes.IndexByte(data[start:], ':')) + 1
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return data
+		}
+
+		if key != "name" {
+			continue
+		}
+
+		var name string
+		if json.Unmarshal(value, &name) != nil || name != from {
+			return data
+		}
+
+		end := decoder.InputOffset()
+		start += int64(bytes.Index(data[start:end], value))
+
+		replacement, _ := json.Marshal(to)
+
+		return slices.Concat(data[:start], replacement, data[start+int64(len(value)):])
+	}
+
+	return data
+}
+
+// confirm pergunta se a remoção deve continuar; o padrão é não remover
+func confirm(input *bufio.Reader, count int) (bool, error) {
+	output.Printf("❓ Remover %d teste(s)? [s/N]: ", count)
+
+	answer, err := input.ReadString('\n')
+	if err != nil && answer == "" {
+		output.Println()
+
+		if errors.Is(err, io.EOF) {
+			return false, exitcode.New(exitcode.Config, "confirmação não recebida (use --force para remover sem confirmar)")
+		}
+
+		return false, fmt.Errorf("erro ao ler confirmação: %w", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "s" || answer == "sim" || answer == "y" || answer == "yes", nil
+}
@@ -0,0 +1,64 @@
+package test
+
+import "testing"
+
+func TestRenameField(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "troca apenas o valor",
+			data: "{\n  \"name\": \"rascunho\",\n  \"route_key\": \"pedidos\"\n}\n",
+			want: "{\n  \"name\": \"pedido-criado\",\n  \"route_key\": \"pedidos\"\n}\n",
+		},
+		{
+			name: "preserva números grandes e a ordem das chaves",
+			data: `{"json_pool":{"id":12345678901234567891,"name":"rascunho"},"name" : "rascunho"}`,
+			want: `{"json_pool":{"id":12345678901234567891,"name":"rascunho"},"name" : "pedido-criado"}`,
+		},
+		{
+			name: "nome diferente do arquivo",
+			data: `{"name":"Pedido criado","route_key":"pedidos"}`,
+			want: `{"name":"Pedido criado","route_key":"pedidos"}`,
+		},
+		{
+			name: "nome com escape",
+			data: `{"name":"rasc\u0075nho"}`,
+			want: `{"name":"pedido-criado"}`,
+		},
+		{name: "sem campo name", data: `{"route_key":"rascunho"}`, want: `{"route_key":"rascunho"}`},
+		{name: "name não texto", data: `{"name":1}`, want: `{"name":1}`},
+		{name: "não objeto", data: `["rascunho"]`, want: `["rascunho"]`},
+		{name: "json inválido", data: `{"name":`, want: `{"name":`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(renameField([]byte(tt.data), "rascunho", "pedido-criado")); got != tt.want {
+				t.Errorf("renameField = %s, esperado %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "pedido-criado"},
+		{name: "pedido.v2"},
+		{name: "", wantErr: true},
+		{name: "  ", wantErr: true},
+		{name: "../segredo", wantErr: true},
+		{name: `dir\teste`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := validName(tt.name); (err != nil) != tt.wantErr {
+			t.Errorf("validName(%q) erro = %v, esperado erro: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
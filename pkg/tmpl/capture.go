@@ -0,0 +1,99 @@
+package tmpl
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/maxwelbm/rabbix/pkg/jpath"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+)
+
+// Sources são os dados de uma mensagem de onde as variáveis podem ser capturadas
+type Sources struct {
+	// Payload é o corpo publicado; nil quando ele não é JSON
+	Payload any
+	Headers map[string]any
+	// Reply é o payload da resposta no modo request/reply; nil fora dele
+	Reply any
+	// HasReply indica que a mensagem teve resposta, mesmo que ela não seja JSON
+	HasReply bool
+}
+
+// SourcesOf monta as origens a partir da mensagem publicada, já com os
+// placeholders e o --mock aplicados
+func SourcesOf(tc rabbix.TestCase) Sources {
+	sources := Sources{Payload: tc.JSONPool, Headers: tc.Headers}
+
+	if tc.Payload != nil {
+		sources.Payload = nil
+
+		if len(tc.Payload.JSON) > 0 {
+			_ = json.Unmarshal(tc.Payload.JSON, &sources.Payload)
+		}
+	}
+
+	return sources
+}
+
+// WithReply adiciona o payload da resposta, interpretado como JSON quando possível
+func (s Sources) WithReply(body []byte) Sources {
+	s.HasReply = true
+	s.Reply = nil
+
+	_ = json.Unmarshal(body, &s.Reply)
+
+	return s
+}
+
+// Capture avalia as regras sobre a mensagem, guarda os valores nas variáveis
+// e os retorna. Nenhuma variável é gravada quando alguma regra falha
+func (e *Engine) Capture(rules map[string]rabbix.Capture, sources Sources) (map[string]any, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	captured := make(map[string]any, len(rules))
+
+	for _, name := range rabbix.CaptureNames(rules) {
+		rule := rules[name]
+
+		var root any
+
+		switch rule.Source() {
+		case rabbix.CaptureHeaders:
+			root = sources.Headers
+		case rabbix.CaptureReply:
+			if !sources.HasReply {
+				return nil, fmt.Errorf("capture '%s': from reply exige o modo request/reply (--rpc)", name)
+			}
+
+			root = sources.Reply
+		default:
+			root = sources.Payload
+		}
+
+		if root == nil {
+			return nil, fmt.Errorf("capture '%s': %s não é JSON", name, rule.Source())
+		}
+
+		value, found, err := jpath.Get(root, rule.Path)
+		if err != nil {
+			return nil, fmt.Errorf("capture '%s': %w", name, err)
+		}
+
+		if !found {
+			return nil, fmt.Errorf("capture '%s': caminho '%s' não encontrado em %s", name, rule.Path, rule.Source())
+		}
+
+		captured[name] = value
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for name, value := range captured {
+		e.vars[name] = value
+	}
+
+	return captured, nil
+}
@@ -0,0 +1,419 @@
+package tmpl
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math"
+	mrand "math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/maxwelbm/rabbix/pkg/jpath"
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+)
+
+// varsPrefix identifica os placeholders de variáveis, ex: {{vars.orderId}}
+const varsPrefix = "vars."
+
+// Engine avalia placeholders como "{{uuid}}" e "{{int 1 100}}" dentro dos
+// valores de um caso de teste. Um mesmo Engine deve ser usado durante toda a
+// execução para que "{{seq}}" avance entre as mensagens e as variáveis
+// definidas fiquem disponíveis em "{{vars.nome}}"
+type Engine struct {
+	mutex sync.Mutex
+	seq   int64
+	rng   *mrand.Rand
+	vars  map[string]any
+}
+
+// funcs são as funções disponíveis nos placeholders
+var funcs = map[string]func(e *Engine, args []string) (any, error){
+	"uuid":   uuidFunc,
+	"int":    intFunc,
+	"float":  floatFunc,
+	"bool":   boolFunc,
+	"string": stringFunc,
+	"now":    nowFunc,
+	"env":    envFunc,
+	"seq": func(e *Engine, _ []string) (any, error) {
+		return e.seq, nil
+	},
+}
+
+func New() *Engine {
+	return &Engine{
+		rng:  mrand.New(mrand.NewSource(time.Now().UnixNano())),
+		vars: map[string]any{},
+	}
+}
+
+// Seed reinicia o gerador de int, float, bool e string com a semente informada,
+// para que execuções com o mesmo --seed gerem os mesmos valores
+func (e *Engine) Seed(seed int64) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.rng = mrand.New(mrand.NewSource(seed))
+}
+
+// SetVar define uma variável disponível nos placeholders como {{vars.nome}}
+func (e *Engine) SetVar(name string, value any) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.vars[name] = value
+}
+
+// SetVarPairs define as variáveis informadas no formato 'nome=valor', como as da flag --var
+func (e *Engine) SetVarPairs(pairs []string) error {
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("variável inválida '%s' (esperado 'nome=valor')", pair)
+		}
+
+		e.SetVar(strings.TrimSpace(name), value)
+	}
+
+	return nil
+}
+
+// Vars retorna uma cópia das variáveis definidas
+func (e *Engine) Vars() map[string]any {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	vars := make(map[string]any, len(e.vars))
+	for name, value := range e.vars {
+		vars[name] = value
+	}
+
+	return vars
+}
+
+// Render avalia os placeholders de um valor qualquer, como os valores
+// esperados de um bloco expect, sem avançar o {{seq}}
+func (e *Engine) Render(value any) (any, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.render(value)
+}
+
+// RenderTestCase devolve uma cópia do caso de teste com os placeholders do
+// json_pool e dos headers avaliados. Cada chamada representa uma nova mensagem
+func (e *Engine) RenderTestCase(tc rabbix.TestCase) (rabbix.TestCase, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.seq++
+
+	pool, err := e.render(tc.JSONPool)
+	if err != nil {
+		return tc, fmt.Errorf("json_pool: %w", err)
+	}
+
+	headers, err := e.render(tc.Headers)
+	if err != nil {
+		return tc, fmt.Errorf("headers: %w", err)
+	}
+
+	tc.JSONPool, _ = pool.(map[string]any)
+	tc.Headers, _ = headers.(map[string]any)
+
+	return tc, nil
+}
+
+// render percorre objetos e arrays avaliando as strings encontradas
+func (e *Engine) render(value any) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		if v == nil {
+			return v, nil
+		}
+
+		// Chaves em ordem, para que o gerador seja consumido sempre na mesma sequência
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		out := make(map[string]any, len(v))
+		for _, key := range keys {
+			item := v[key]
+
+			rendered, err := e.render(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+
+			out[key] = rendered
+		}
+
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			rendered, err := e.render(item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+
+			out[i] = rendered
+		}
+
+		return out, nil
+	case string:
+		return e.renderString(v)
+	default:
+		return v, nil
+	}
+}
+
+// renderString avalia os placeholders de uma string. Quando a string é
+// apenas um placeholder, o tipo do valor gerado é preservado no JSON
+func (e *Engine) renderString(s string) (any, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(trimmed, "{{") && strings.HasSuffix(trimmed, "}}") &&
+		strings.Count(trimmed, "{{") == 1 {
+		return e.eval(trimmed[2 : len(trimmed)-2])
+	}
+
+	var out strings.Builder
+
+	rest := s
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			break
+		}
+
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("placeholder sem fechamento em '%s'", s)
+		}
+
+		value, err := e.eval(rest[start+2 : start+end])
+		if err != nil {
+			return nil, err
+		}
+
+		out.WriteString(rest[:start])
+		out.WriteString(fmt.Sprint(value))
+		rest = rest[start+end+2:]
+	}
+
+	out.WriteString(rest)
+
+	return out.String(), nil
+}
+
+// eval executa a expressão de um placeholder, ex: `int 1 100` ou `now "2006-01-02"`
+func (e *Engine) eval(expr string) (any, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("placeholder vazio")
+	}
+
+	if name, ok := strings.CutPrefix(tokens[0], varsPrefix); ok && len(tokens) == 1 {
+		return e.variable(name)
+	}
+
+	fn, ok := funcs[tokens[0]]
+	if !ok {
+		return nil, fmt.Errorf("função desconhecida '%s' no placeholder '{{%s}}'", tokens[0], expr)
+	}
+
+	value, err := fn(e, tokens[1:])
+	if err != nil {
+		return nil, fmt.Errorf("{{%s}}: %w", strings.TrimSpace(expr), err)
+	}
+
+	return value, nil
+}
+
+// variable resolve {{vars.nome}}, aceitando caminhos como {{vars.pedido.itens[0].sku}}
+func (e *Engine) variable(path string) (any, error) {
+	value, found, err := jpath.Get(e.vars, path)
+	if err != nil {
+		return nil, fmt.Errorf("{{%s%s}}: %w", varsPrefix, path, err)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("variável '%s' não definida", path)
+	}
+
+	return value, nil
+}
+
+// tokenize separa a expressão por espaços, respeitando argumentos entre aspas
+func tokenize(expr string) ([]string, error) {
+	var tokens []string
+
+	rest := strings.TrimSpace(expr)
+	for rest != "" {
+		if rest[0] == '"' {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, fmt.Errorf("argumento entre aspas inválido em '%s'", expr)
+			}
+
+			token, _ := strconv.Unquote(quoted)
+			tokens = append(tokens, token)
+			rest = strings.TrimSpace(rest[len(quoted):])
+
+			continue
+		}
+
+		token, remaining, _ := strings.Cut(rest, " ")
+		tokens = append(tokens, token)
+		rest = strings.TrimSpace(remaining)
+	}
+
+	return tokens, nil
+}
+
+func uuidFunc(_ *Engine, _ []string) (any, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	// Versão 4, variante RFC 4122
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+func intFunc(e *Engine, args []string) (any, error) {
+	minValue, maxValue := int64(0), int64(1000000)
+
+	if len(args) > 0 {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("uso: int <min> <max>")
+		}
+
+		var err error
+		if minValue, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("mínimo inválido '%s'", args[0])
+		}
+
+		if maxValue, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+			return nil, fmt.Errorf("máximo inválido '%s'", args[1])
+		}
+
+		if maxValue < minValue {
+			return nil, fmt.Errorf("máximo %d menor que o mínimo %d", maxValue, minValue)
+		}
+
+		// A largura max-min+1 precisa caber em um int64
+		if uint64(maxValue)-uint64(minValue) >= math.MaxInt64 {
+			return nil, fmt.Errorf("intervalo de %d a %d grande demais", minValue, maxValue)
+		}
+	}
+
+	return minValue + e.rng.Int63n(maxValue-minValue+1), nil
+}
+
+func floatFunc(e *Engine, args []string) (any, error) {
+	minValue, maxValue := 0.0, 100000.0
+
+	if len(args) > 0 {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("uso: float <min> <max>")
+		}
+
+		var err error
+		if minValue, err = strconv.ParseFloat(args[0], 64); err != nil {
+			return nil, fmt.Errorf("mínimo inválido '%s'", args[0])
+		}
+
+		if maxValue, err = strconv.ParseFloat(args[1], 64); err != nil {
+			return nil, fmt.Errorf("máximo inválido '%s'", args[1])
+		}
+
+		if maxValue < minValue {
+			return nil, fmt.Errorf("máximo %v menor que o mínimo %v", maxValue, minValue)
+		}
+
+		// Também rejeita NaN e infinitos, inclusive na largura do intervalo
+		if width := maxValue - minValue; math.IsNaN(width) || math.IsInf(width, 0) {
+			return nil, fmt.Errorf("intervalo de %v a %v inválido", minValue, maxValue)
+		}
+	}
+
+	return minValue + e.rng.Float64()*(maxValue-minValue), nil
+}
+
+func boolFunc(e *Engine, _ []string) (any, error) {
+	return e.rng.Intn(2) == 0, nil
+}
+
+func stringFunc(e *Engine, args []string) (any, error) {
+	n := 12
+
+	if len(args) > 0 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 0 {
+			return nil, fmt.Errorf("tamanho inválido '%s'", args[0])
+		}
+	}
+
+	letters := []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+
+	b := make([]rune, n)
+	for i := range b {
+		b[i] = letters[e.rng.Intn(len(letters))]
+	}
+
+	return string(b), nil
+}
+
+// nowFunc aceita um layout do pacote time; "unix" e "unixms" geram números
+func nowFunc(_ *Engine, args []string) (any, error) {
+	now := time.Now()
+
+	if len(args) == 0 {
+		return now.Format(time.RFC3339), nil
+	}
+
+	switch args[0] {
+	case "unix":
+		return now.Unix(), nil
+	case "unixms":
+		return now.UnixMilli(), nil
+	default:
+		return now.Format(args[0]), nil
+	}
+}
+
+func envFunc(_ *Engine, args []string) (any, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return nil, fmt.Errorf("uso: env \"NOME\" [\"padrão\"]")
+	}
+
+	if value, ok := os.LookupEnv(args[0]); ok {
+		return value, nil
+	}
+
+	if len(args) == 2 {
+		return args[1], nil
+	}
+
+	return nil, fmt.Errorf("variável de ambiente '%s' não definida", args[0])
+}
@@ -0,0 +1,296 @@
+package tmpl
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/maxwelbm/rabbix/pkg/rabbix"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    []string
+		wantErr bool
+	}{
+		{name: "vazio", expr: "  ", want: nil},
+		{name: "função sem argumentos", expr: "uuid", want: []string{"uuid"}},
+		{name: "argumentos", expr: " int  1 100 ", want: []string{"int", "1", "100"}},
+		{name: "argumento entre aspas", expr: `now "2006-01-02 15:04"`, want: []string{"now", "2006-01-02 15:04"}},
+		{name: "aspas escapadas", expr: `env "A" "x \"y\""`, want: []string{"env", "A", `x "y"`}},
+		{name: "aspas sem fechar", expr: `env "A`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokenize(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tokenize(%q) erro = %v, esperado erro: %v", tt.expr, err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize(%q) = %q, esperado %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Setenv("RABBIX_TMPL_TEST", "valor")
+
+	tests := []struct {
+		name    string
+		value   any
+		want    any
+		wantErr bool
+	}{
+		{name: "sem placeholder", value: "texto", want: "texto"},
+		{name: "não string", value: float64(3), want: float64(3)},
+		{name: "variável mantém o tipo", value: "{{vars.id}}", want: float64(42)},
+		{name: "variável com caminho", value: "{{ vars.order.items[0] }}", want: "A1"},
+		{name: "variável no meio do texto", value: "pedido-{{vars.id}}", want: "pedido-42"},
+		{name: "env", value: `{{env "RABBIX_TMPL_TEST"}}`, want: "valor"},
+		{name: "env com padrão", value: `{{env "RABBIX_TMPL_AUSENTE" "padrão"}}`, want: "padrão"},
+		{name: "int com intervalo de um valor", value: "{{int 5 5}}", want: int64(5)},
+		{name: "float com intervalo de um valor", value: "{{float 1.5 1.5}}", want: 1.5},
+		{
+			name:  "objetos e arrays",
+			value: map[string]any{"ids": []any{"{{vars.id}}", "x"}},
+			want:  map[string]any{"ids": []any{float64(42), "x"}},
+		},
+		{name: "variável não definida", value: "{{vars.ausente}}", wantErr: true},
+		{name: "env não definida", value: `{{env "RABBIX_TMPL_AUSENTE"}}`, wantErr: true},
+		{name: "função desconhecida", value: "{{nada}}", wantErr: true},
+		{name: "placeholder vazio", value: "{{ }}", wantErr: true},
+		{name: "placeholder sem fechamento", value: "a {{uuid}} b {{uuid", wantErr: true},
+		{name: "erro dentro de array", value: []any{"{{nada}}"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := New()
+			engine.SetVar("id", float64(42))
+			engine.SetVar("order", map[string]any{"items": []any{"A1"}})
+
+			got, err := engine.Render(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render(%#v) erro = %v, esperado erro: %v", tt.value, err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Render(%#v) = %#v, esperado %#v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderRandom(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		check func(value any) bool
+	}{
+		{
+			name:  "uuid v4",
+			value: "{{uuid}}",
+			check: func(value any) bool {
+				s, ok := value.(string)
+				return ok && len(s) == 36 && s[14] == '4'
+			},
+		},
+		{
+			name:  "int no intervalo",
+			value: "{{int -3 3}}",
+			check: func(value any) bool {
+				n, ok := value.(int64)
+				return ok && n >= -3 && n <= 3
+			},
+		},
+		{
+			name:  "int no intervalo máximo aceito",
+			value: "{{int 0 9223372036854775806}}",
+			check: func(value any) bool {
+				n, ok := value.(int64)
+				return ok && n >= 0
+			},
+		},
+		{
+			name:  "float no intervalo",
+			value: "{{float 0.5 1}}",
+			check: func(value any) bool {
+				f, ok := value.(float64)
+				return ok && f >= 0.5 && f <= 1
+			},
+		},
+		{
+			name:  "string com tamanho",
+			value: "{{string 8}}",
+			check: func(value any) bool {
+				s, ok := value.(string)
+				return ok && len(s) == 8
+			},
+		},
+		{
+			name:  "bool",
+			value: "{{bool}}",
+			check: func(value any) bool {
+				_, ok := value.(bool)
+				return ok
+			},
+		},
+		{
+			name:  "now unix",
+			value: "{{now unix}}",
+			check: func(value any) bool {
+				_, ok := value.(int64)
+				return ok
+			},
+		},
+	}
+
+	engine := New()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 50 {
+				got, err := engine.Render(tt.value)
+				if err != nil {
+					t.Fatalf("Render(%q) erro inesperado: %v", tt.value, err)
+				}
+
+				if !tt.check(got) {
+					t.Fatalf("Render(%q) = %#v fora do esperado", tt.value, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRangeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr string
+	}{
+		{name: "int com um argumento", value: "{{int 1}}", wantErr: "uso: int"},
+		{name: "int mínimo inválido", value: "{{int a 1}}", wantErr: "mínimo inválido"},
+		{name: "int máximo menor", value: "{{int 10 1}}", wantErr: "menor que o mínimo"},
+		{
+			name:    "int largura acima de int64",
+			value:   "{{int -9223372036854775808 9223372036854775807}}",
+			wantErr: "grande demais",
+		},
+		{name: "int largura igual a MaxInt64", value: "{{int 0 9223372036854775807}}", wantErr: "grande demais"},
+		{name: "float máximo menor", value: "{{float 2 1}}", wantErr: "menor que o mínimo"},
+		{name: "float NaN", value: "{{float NaN 1}}", wantErr: "inválido"},
+		{name: "float infinito", value: "{{float 0 +Inf}}", wantErr: "inválido"},
+		{name: "float largura infinita", value: "{{float -1e308 1e308}}", wantErr: "inválido"},
+		{name: "string tamanho negativo", value: "{{string -1}}", wantErr: "tamanho inválido"},
+	}
+
+	engine := New()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := engine.Render(tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Render(%q) erro = %v, esperado contendo %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderTestCaseSeq(t *testing.T) {
+	engine := New()
+	tc := rabbix.TestCase{
+		JSONPool: map[string]any{"seq": "{{seq}}"},
+		Headers:  map[string]any{"x-seq": "n{{seq}}"},
+	}
+
+	for want := int64(1); want <= 3; want++ {
+		got, err := engine.RenderTestCase(tc)
+		if err != nil {
+			t.Fatalf("RenderTestCase erro inesperado: %v", err)
+		}
+
+		if got.JSONPool["seq"] != want {
+			t.Errorf("seq = %#v, esperado %d", got.JSONPool["seq"], want)
+		}
+
+		if got.Headers["x-seq"] != "n"+strconv.FormatInt(want, 10) {
+			t.Errorf("x-seq = %#v, esperado n%d", got.Headers["x-seq"], want)
+		}
+	}
+
+	// Render não avança o {{seq}}
+	if got, _ := engine.Render("{{seq}}"); got != int64(3) {
+		t.Errorf("Render({{seq}}) = %#v, esperado 3", got)
+	}
+
+	if tc.JSONPool["seq"] != "{{seq}}" {
+		t.Errorf("RenderTestCase alterou o caso de teste original: %#v", tc.JSONPool)
+	}
+}
+
+func TestIntFuncBounds(t *testing.T) {
+	// A verificação de largura não pode rejeitar intervalos válidos nos extremos do int64
+	tests := []struct {
+		name     string
+		min, max int64
+	}{
+		{name: "extremo negativo", min: math.MinInt64, max: -2},
+		{name: "extremo positivo", min: 1, max: math.MaxInt64},
+		{name: "cruza o zero", min: math.MinInt64 / 4, max: math.MaxInt64 / 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []string{strconv.FormatInt(tt.min, 10), strconv.FormatInt(tt.max, 10)}
+
+			value, err := intFunc(New(), args)
+			if err != nil {
+				t.Fatalf("intFunc(%v) erro inesperado: %v", args, err)
+			}
+
+			if n := value.(int64); n < tt.min || n > tt.max {
+				t.Errorf("intFunc(%v) = %d fora do intervalo", args, n)
+			}
+		})
+	}
+}
+
+func TestSeedReproducible(t *testing.T) {
+	// Com a mesma semente, como no --seed do run, os valores aleatórios se repetem
+	template := map[string]any{"id": "{{int 1 1000000000}}", "preco": "{{float 1 100}}", "codigo": "{{string 12}}"}
+
+	render := func(seed int64) any {
+		engine := New()
+		engine.Seed(seed)
+
+		var values []any
+
+		for range 3 {
+			value, err := engine.Render(template)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+
+			values = append(values, value)
+		}
+
+		return values
+	}
+
+	first := render(42)
+	if second := render(42); !reflect.DeepEqual(first, second) {
+		t.Errorf("mesma semente gerou %v e %v", first, second)
+	}
+
+	if other := render(7); reflect.DeepEqual(first, other) {
+		t.Errorf("sementes diferentes geraram os mesmos valores %v", other)
+	}
+}